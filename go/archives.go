@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// archiveDateLayout is the layout of the -after and -before dates
+const archiveDateLayout = "2006-01-02"
+
+// archiveFilter builds an ArchiveFilter from the -after, -before, -hashtag and -query flags. before is inclusive, so
+// messages posted on that day are exported too.
+func archiveFilter(after string, before string, hashtag string, query string) (groupsclient.ArchiveFilter, error) {
+	f := groupsclient.ArchiveFilter{Hashtag: hashtag, Query: query}
+	if after != "" {
+		t, err := time.Parse(archiveDateLayout, after)
+		if err != nil {
+			return f, fmt.Errorf("--after %q is not a YYYY-MM-DD date", after)
+		}
+		f.After = t
+	}
+	if before != "" {
+		t, err := time.Parse(archiveDateLayout, before)
+		if err != nil {
+			return f, fmt.Errorf("--before %q is not a YYYY-MM-DD date", before)
+		}
+		f.Before = t.AddDate(0, 0, 1)
+	}
+	return f, nil
+}
+
+// archiveSearch prints the messages in the archives of the subgroup name that match query
+func archiveSearch(client *groupsclient.GroupsClient, name string, query string) error {
+	if query == "" {
+		return fmt.Errorf("--query not specified")
+	}
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	msgs, total, err := client.SearchArchives(group.ID, query)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("archiveSearch: %d message(s) in %s match %q\n", total, group.Name, query)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MSG\tTOPIC\tPOSTED\tFROM\tSUBJECT")
+	for _, msg := range msgs {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\n", msg.MsgNum, msg.TopicID, msg.Created, msg.Name, msg.Subject)
+	}
+	return w.Flush()
+}
+
+// archiveExport writes the messages in the archives of the subgroup name selected by f to out as mbox, maildir or
+// jsonl. When format is empty it is taken from the extension of out, defaulting to mbox. out is a directory for
+// maildir and may only be empty, meaning stdout, for the other formats.
+func archiveExport(client *groupsclient.GroupsClient, name string, f groupsclient.ArchiveFilter, out string, format string) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(out), ".")
+	}
+	switch format {
+	case "", "mbox":
+		format = "mbox"
+	case "maildir", "jsonl":
+	default:
+		return fmt.Errorf("unknown archive format %q, must be mbox, maildir or jsonl", format)
+	}
+	if format == "maildir" && out == "" {
+		return fmt.Errorf("maildir needs --out, the directory to write to")
+	}
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	msgs, err := client.GetArchive(group.ID, f)
+	if err != nil {
+		return err
+	}
+
+	if format == "maildir" {
+		if err := groupsclient.WriteMaildir(out, msgs, group.Email); err != nil {
+			return err
+		}
+	} else {
+		w := os.Stdout
+		if out != "" {
+			if w, err = os.Create(out); err != nil {
+				return err
+			}
+		}
+		if format == "mbox" {
+			err = groupsclient.WriteMbox(w, msgs, group.Email)
+		} else {
+			err = groupsclient.WriteJSONLines(w, msgs)
+		}
+		if out == "" {
+			return err
+		}
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if out != "" {
+		fmt.Printf("archiveExport: wrote %d message(s) from %s to %s\n", len(msgs), group.Name, out)
+	}
+	return nil
+}
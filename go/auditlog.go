@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// AuditEntry is a single administrative action recorded in the audit log
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	GroupID   int       `json:"group_id"`
+	GroupName string    `json:"group_name"`
+	Target    string    `json:"target"`
+	Reason    string    `json:"reason,omitempty"`
+	Result    string    `json:"result"`
+}
+
+// AuditLog appends AuditEntry records to a file as JSON lines
+type AuditLog struct {
+	file *os.File
+	enc  *json.Encoder
+}
+
+// OpenAuditLog opens, creating if needed, the audit log at path for appending
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record writes entry to the audit log, stamping it with the current time if it has none
+func (a *AuditLog) Record(entry AuditEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	return a.enc.Encode(entry)
+}
+
+// Close closes the underlying audit log file
+func (a *AuditLog) Close() error {
+	return a.file.Close()
+}
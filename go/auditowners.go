@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"main/groupsclient"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// auditOwners writes the ownership of each subgroup matching groupRe to out, or stdout when out is empty, as text,
+// csv or json. With flaggedOnly only groups with ownership problems are included.
+func auditOwners(client *groupsclient.GroupsClient, groupRe string, flaggedOnly bool, out string, format string) error {
+	var re *regexp.Regexp
+	if groupRe != "" {
+		var err error
+		if re, err = regexp.Compile(groupRe); err != nil {
+			return fmt.Errorf("invalid --filter %q: %w", groupRe, err)
+		}
+	}
+	audit, err := client.AuditOwnership(re)
+	if err != nil {
+		return err
+	}
+	if flaggedOnly {
+		flagged := make([]groupsclient.GroupOwnership, 0, len(audit))
+		for _, o := range audit {
+			if len(o.Flags) > 0 {
+				flagged = append(flagged, o)
+			}
+		}
+		audit = flagged
+	}
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return writeOwnershipText(w, audit)
+	case "csv":
+		return writeOwnershipCSV(w, audit)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(audit)
+	default:
+		return fmt.Errorf("unknown format %q, use text, csv or json", format)
+	}
+}
+
+func flagsString(flags []groupsclient.OwnershipFlag) string {
+	names := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		names = append(names, string(flag))
+	}
+	return strings.Join(names, ",")
+}
+
+func writeOwnershipText(w io.Writer, audit []groupsclient.GroupOwnership) error {
+	flagged := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tROLE\tNAME\tEMAIL\tUSER STATUS\tMEMBERSHIP UPDATED\tFLAGS")
+	for _, o := range audit {
+		if len(o.Flags) > 0 {
+			flagged++
+		}
+		if len(o.Owners)+len(o.Moderators) == 0 {
+			fmt.Fprintf(tw, "%s\t\t\t\t\t\t%s\n", o.Group, strings.TrimSpace(flagsString(o.Flags)+" "+o.Error))
+		}
+		for role, holders := range [][]groupsclient.RoleHolder{o.Owners, o.Moderators} {
+			for _, h := range holders {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", o.Group, []string{"owner", "moderator"}[role], h.Name,
+					h.Email, h.UserStatus, h.MembershipUpdated, flagsString(o.Flags))
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d of %d group(s) flagged\n"+
+		"MEMBERSHIP UPDATED is when the membership last changed, groups.io does not report when an owner was last active\n",
+		flagged, len(audit))
+	return err
+}
+
+func writeOwnershipCSV(w io.Writer, audit []groupsclient.GroupOwnership) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"group", "group_id", "subs_count", "flags", "role", "name", "email", "user_status", "status", "membership_updated", "unreachable", "error"})
+	for _, o := range audit {
+		group := []string{o.Group, strconv.Itoa(o.GroupID), strconv.Itoa(o.SubsCount), flagsString(o.Flags)}
+		if len(o.Owners)+len(o.Moderators) == 0 {
+			cw.Write(append(group, "", "", "", "", "", "", "", o.Error))
+		}
+		for role, holders := range [][]groupsclient.RoleHolder{o.Owners, o.Moderators} {
+			for _, h := range holders {
+				cw.Write(append(append([]string{}, group...), []string{"owner", "moderator"}[role], h.Name, h.Email,
+					h.UserStatus, h.Status, h.MembershipUpdated, strconv.FormatBool(h.Unreachable), o.Error))
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
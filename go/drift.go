@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"os"
+)
+
+// driftReport compares the spec at specPath with groups.io, without changing anything, and writes the report in
+// format to out, or stdout when out is empty. Returns the exit code of the command.
+func driftReport(client *groupsclient.GroupsClient, specPath string, out string, format string) int {
+	spec, err := groupsclient.LoadSpec(specPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drift: %v\n", err)
+		return exitError
+	}
+	report, err := client.DetectDrift(spec, specPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drift: Error fetching live state: %v\n", err)
+		return exitError
+	}
+	w := os.Stdout
+	if out != "" {
+		if w, err = os.Create(out); err != nil {
+			fmt.Fprintf(os.Stderr, "drift: %v\n", err)
+			return exitError
+		}
+		defer w.Close()
+	}
+	if err := report.Write(w, format); err != nil {
+		fmt.Fprintf(os.Stderr, "drift: writing report: %v\n", err)
+		return exitError
+	}
+	if report.HasDrift() {
+		return exitDrift
+	}
+	return exitInSync
+}
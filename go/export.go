@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// exportSpec writes the live state of the org's subgroups matching groupRe to out, or stdout when out is empty, in
+// format. When format is empty it is taken from the extension of out, defaulting to YAML.
+func exportSpec(client *groupsclient.GroupsClient, groupRe string, withMembers bool, out string, format string) error {
+	var include func(string) bool
+	if groupRe != "" {
+		re, err := regexp.Compile(groupRe)
+		if err != nil {
+			return fmt.Errorf("invalid --filter %q: %w", groupRe, err)
+		}
+		include = re.MatchString
+	}
+	spec, err := client.ExportSpec(include, withMembers)
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(out), ".")
+	}
+	if out == "" {
+		return spec.Write(os.Stdout, format)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := spec.Write(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("export: wrote %d group(s) to %s\n", len(spec.Groups), out)
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"os"
+	"path"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+// filesList prints every file and folder in the Files section of the subgroup name
+func filesList(client *groupsclient.GroupsClient, name string) error {
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	tree, err := client.GetFileTree(group.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("filesList: %s has %d file(s) and folder(s)\n", group.Name, len(tree))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tSIZE\tUPDATED")
+	for _, entry := range tree {
+		if entry.IsFolder {
+			fmt.Fprintf(w, "%s/\t-\t%s\n", entry.Path, entry.Updated)
+		} else {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", entry.Path, entry.Size, entry.Updated)
+		}
+	}
+	return w.Flush()
+}
+
+// filesUpload uploads localFile into the folder at folderPath in the subgroup name
+func filesUpload(client *groupsclient.GroupsClient, subs []groupsclient.MemberInfo, actor string, name string,
+	folderPath string, localFile string, audit *AuditLog) error {
+	if localFile == "" {
+		return fmt.Errorf("--localFile not specified")
+	}
+	sub, err := adminSubFor(subs, name, groupsclient.UploadFileAction.Permitted)
+	if err != nil {
+		return err
+	}
+	folder, err := client.FindFile(sub.GroupID, folderPath)
+	if err != nil {
+		return err
+	}
+	if !folder.IsFolder {
+		return fmt.Errorf("%s is not a folder", folderPath)
+	}
+	target := path.Join("/", folder.Path, filepath.Base(localFile))
+	entry, err := client.UploadFile(sub.GroupID, folder.ID, localFile)
+	recordAuditResult(audit, fileAuditEntry(actor, groupsclient.UploadFileAction, sub, target), err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("filesUpload: uploaded %s to %s in %s, %d bytes\n", localFile, target, sub.GroupName, entry.Size)
+	return nil
+}
+
+// filesDownload downloads the file at filePath in the subgroup name to out, or to its name in the current directory
+// when out is empty. The download is written to a .part file that only replaces out once it is complete and has the
+// size and checksum groups.io reports.
+func filesDownload(client *groupsclient.GroupsClient, name string, filePath string, out string) error {
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	entry, err := client.FindFile(group.ID, filePath)
+	if err != nil {
+		return err
+	}
+	if entry.IsFolder {
+		return fmt.Errorf("%s is a folder, use -cmd filesMirror to download folders", filePath)
+	}
+	if out == "" {
+		// the name comes from the server, never let it climb out of the current directory
+		out = filepath.Base(filepath.FromSlash(entry.Name))
+		if !filepath.IsLocal(out) {
+			return fmt.Errorf("%q is not a usable file name, use --out", entry.Name)
+		}
+	}
+	partPath := out + ".part"
+	f, err := os.Create(partPath)
+	if err != nil {
+		return err
+	}
+	err = client.DownloadFile(*entry, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = groupsclient.VerifyFile(partPath, *entry)
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return err
+	}
+	if err := os.Rename(partPath, out); err != nil {
+		return err
+	}
+	fmt.Printf("filesDownload: wrote %s from %s to %s\n", entry.Path, group.Name, out)
+	return nil
+}
+
+// filesDelete deletes the file or folder at filePath in the subgroup name
+func filesDelete(client *groupsclient.GroupsClient, subs []groupsclient.MemberInfo, actor string, name string,
+	filePath string, audit *AuditLog) error {
+	sub, err := adminSubFor(subs, name, groupsclient.DeleteFileAction.Permitted)
+	if err != nil {
+		return err
+	}
+	entry, err := client.FindFile(sub.GroupID, filePath)
+	if err != nil {
+		return err
+	}
+	if entry.ID == 0 {
+		return fmt.Errorf("the root of the Files section can not be deleted")
+	}
+	if entry.IsFolder {
+		fmt.Printf("filesDelete: deleting the folder %s and everything in it from %s\n", entry.Path, sub.GroupName)
+	} else {
+		fmt.Printf("filesDelete: deleting %s, %d bytes, from %s\n", entry.Path, entry.Size, sub.GroupName)
+	}
+	ContinuePrompt()
+	err = client.DeleteFile(sub.GroupID, entry.ID)
+	recordAuditResult(audit, fileAuditEntry(actor, groupsclient.DeleteFileAction, sub, entry.Path), err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("filesDelete: deleted %s from %s\n", entry.Path, sub.GroupName)
+	return nil
+}
+
+// fileAuditEntry describes action, taken by actor on target, a path in sub's group, in the audit log
+func fileAuditEntry(actor string, action groupsclient.FileAction, sub groupsclient.MemberInfo, target string) AuditEntry {
+	return AuditEntry{Actor: actor, Action: "files " + string(action), GroupID: sub.GroupID, GroupName: sub.GroupName, Target: target}
+}
+
+// filesMirror downloads the Files section of the subgroup name to dir. Returns the number of files that failed,
+// running it again resumes them.
+func filesMirror(client *groupsclient.GroupsClient, name string, dir string) (int, error) {
+	if dir == "" {
+		return 0, fmt.Errorf("--out not specified, the directory to mirror into")
+	}
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return 0, err
+	}
+	results, err := client.MirrorFiles(group.ID, dir)
+	if err != nil {
+		return 0, err
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("filesMirror: %s: FAILED %v\n", result.Entry.Path, result.Err)
+			continue
+		}
+		fmt.Printf("filesMirror: %s: %s\n", result.Entry.Path, result.Action)
+	}
+	fmt.Printf("filesMirror: mirrored %d of %d file(s) from %s to %s\n", len(results)-failed, len(results), group.Name, dir)
+	return failed, nil
+}
@@ -2,4 +2,6 @@ module main
 
 go 1.22
 
+require gopkg.in/yaml.v3 v3.0.1
+
 require github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// filterGroups returns the groups in groups whose Name matches the regular expression re
+func filterGroups(re string, groups []groupsclient.Group) ([]groupsclient.Group, error) {
+	if re == "" {
+		return groups, nil
+	}
+	groupsRegExp, err := regexp.Compile(re)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --filter %q: %w", re, err)
+	}
+	filtered := make([]groupsclient.Group, 0, len(groups))
+	for _, group := range groups {
+		if groupsRegExp.MatchString(group.Name) {
+			filtered = append(filtered, group)
+		}
+	}
+	return filtered, nil
+}
+
+// groupsReport prints a table of the settings of each group in groups
+func groupsReport(groups []groupsclient.Group) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTITLE\tPRIVACY\tRESTRICTED\tMODERATED\tSUBS\tFEATURES")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%t\t%d\t%s\n", g.Name, g.Title, strings.TrimPrefix(g.Privacy, "group_privacy_"),
+			g.Restricted, g.Moderated, g.SubsCount, strings.Join(groupFeatures(g), ","))
+	}
+	w.Flush()
+}
+
+// groupFeatures lists the features enabled on g
+func groupFeatures(g groupsclient.Group) []string {
+	features := make([]string, 0)
+	for name, access := range map[string]string{
+		"calendar": g.CalendarAccess,
+		"chat":     g.ChatAccess,
+		"database": g.DatabaseAccess,
+		"files":    g.FilesAccess,
+		"hashtags": g.HashtagsAccess,
+		"members":  g.MemberDirectoryAccess,
+		"photos":   g.PhotosAccess,
+		"polls":    g.PollsAccess,
+		"wiki":     g.WikiAccess,
+	} {
+		if access != "" && access != "access_none" {
+			features = append(features, name)
+		}
+	}
+	sort.Strings(features)
+	return features
+}
+
+// settingsFlag collects repeated -set name=value flags into GroupSettings
+type settingsFlag groupsclient.GroupSettings
+
+func (s settingsFlag) String() string {
+	pairs := make([]string, 0, len(s))
+	for name, value := range s {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (s settingsFlag) Set(pair string) error {
+	name, value, found := strings.Cut(pair, "=")
+	if !found {
+		return fmt.Errorf("setting %q is not of the form name=value", pair)
+	}
+	s[strings.TrimSpace(name)] = value
+	return nil
+}
+
+// printSettings prints settings in name order
+func printSettings(settings groupsclient.GroupSettings) {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %s = %q\n", name, settings[name])
+	}
+}
+
+// groupCreate creates the subgroup name, starting from the settings of the template group like when it is not empty
+// and then applying overrides, recording it in audit
+func groupCreate(client *groupsclient.GroupsClient, actor string, name string, like string,
+	overrides groupsclient.GroupSettings, audit *AuditLog) error {
+	settings := groupsclient.GroupSettings{}
+	if like != "" {
+		template, err := client.FindGroup(like)
+		if err != nil {
+			return err
+		}
+		settings = template.CloneableSettings()
+	}
+	for setting, value := range overrides {
+		settings[setting] = value
+	}
+	fmt.Printf("groupCreate: creating %s with settings\n", name)
+	printSettings(settings)
+	ContinuePrompt()
+	group, err := client.CreateGroup(name, settings)
+	entry := AuditEntry{Actor: actor, Action: "create group", GroupName: name, Target: settingsFlag(settings).String()}
+	if like != "" {
+		entry.Reason = "like " + like
+	}
+	if err == nil {
+		entry.GroupID = group.ID
+	}
+	recordAuditResult(audit, entry, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("groupCreate: created %s [GroupId %d]\n", group.Name, group.ID)
+	return nil
+}
+
+// groupUpdate applies settings to the subgroup name after showing how each setting will change, recording it in audit
+func groupUpdate(client *groupsclient.GroupsClient, actor string, name string, settings groupsclient.GroupSettings,
+	audit *AuditLog) error {
+	if len(settings) == 0 {
+		return fmt.Errorf("no settings given, use -set name=value")
+	}
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	current := group.Settings()
+	fmt.Printf("groupUpdate: changing %s\n", group.Name)
+	for setting, value := range settings {
+		fmt.Printf("  %s: %q -> %q\n", setting, current[setting], value)
+	}
+	ContinuePrompt()
+	_, err = client.UpdateGroup(group.ID, settings)
+	recordAuditResult(audit, AuditEntry{Actor: actor, Action: "update group", GroupID: group.ID, GroupName: group.Name,
+		Target: settingsFlag(settings).String()}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("groupUpdate: updated %s\n", group.Name)
+	return nil
+}
+
+// groupDelete deletes the subgroup name once the user has confirmed by typing its name, after checking the Perms of
+// actor's subscription in subs to it allow deleting the group, and records it in audit
+func groupDelete(client *groupsclient.GroupsClient, subs []groupsclient.MemberInfo, actor string, name string,
+	audit *AuditLog) error {
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	if sub, ok := subFor(subs, group.ID); !ok || !sub.Perms.DeleteGroup {
+		return fmt.Errorf("%s does not have permission to delete %s", actor, group.Name)
+	}
+	fmt.Printf("groupDelete: %s has %d subscribers, deleting it removes its members, archives, files and wiki for good\n",
+		group.Name, group.SubsCount)
+	typed, err := Prompt(fmt.Sprintf("Type the group name, %s, to confirm: ", group.Name))
+	if err != nil || typed != group.Name {
+		return fmt.Errorf("confirmation did not match, %s was not deleted", group.Name)
+	}
+	err = client.DeleteGroup(group.ID)
+	recordAuditResult(audit, AuditEntry{Actor: actor, Action: "delete group", GroupID: group.ID, GroupName: group.Name,
+		Target: group.Name, Reason: fmt.Sprintf("%d subscribers", group.SubsCount)}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("groupDelete: deleted %s\n", group.Name)
+	return nil
+}
@@ -0,0 +1,322 @@
+package groupsclient
+
+import (
+	"bytes"
+	"encoding/json"
+	. "fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Topic is a thread in a group's archives
+// https://groups.io/api#the-topic-object
+type Topic struct {
+	ID                int       `json:"id"`
+	Object            string    `json:"object"`
+	Created           string    `json:"created"`
+	Updated           string    `json:"updated"`
+	GroupID           int       `json:"group_id"`
+	UserID            int       `json:"user_id"`
+	Subject           string    `json:"subject"`
+	Summary           string    `json:"summary"`
+	Name              string    `json:"name"`
+	NumMessages       int       `json:"num_messages"`
+	IsSticky          bool      `json:"is_sticky"`
+	IsModerated       bool      `json:"is_moderated"`
+	Locked            bool      `json:"locked"`
+	MostRecentMessage string    `json:"most_recent_message"`
+	Hashtags          []Hashtag `json:"hashtags"`
+}
+
+// Message is a single message in a group's archives, Body is HTML unless IsPlainText is set
+// https://groups.io/api#the-message-object
+type Message struct {
+	ID             int    `json:"id"`
+	Object         string `json:"object"`
+	Created        string `json:"created"`
+	Updated        string `json:"updated"`
+	UserID         int    `json:"user_id"`
+	GroupID        int    `json:"group_id"`
+	TopicID        int    `json:"topic_id"`
+	MsgNum         int    `json:"msg_num"`
+	Subject        string `json:"subject"`
+	Body           string `json:"body"`
+	IsPlainText    bool   `json:"is_plain_text"`
+	Snippet        string `json:"snippet"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	ReplyTo        string `json:"reply_to"`
+	IsReply        bool   `json:"is_reply"`
+	HasAttachments bool   `json:"has_attachments"`
+}
+
+// HasHashtag reports whether t is tagged with name, with or without its leading #
+func (t Topic) HasHashtag(name string) bool {
+	name = strings.TrimPrefix(name, "#")
+	for _, hashtag := range t.Hashtags {
+		if strings.EqualFold(strings.TrimPrefix(hashtag.Name, "#"), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatedTime parses the time the message was posted
+func (m Message) CreatedTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, m.Created)
+}
+
+// GetTopics returns every topic in the archives of groupId, most recently active first
+// https://groups.io/api#get-topics
+func (c *GroupsClient) GetTopics(groupId int) ([]Topic, int, error) {
+	topics, total, err := getAllPages[Topic](c, Sprintf("/api/v1/gettopics?group_id=%d", groupId))
+	if err != nil {
+		return nil, total, Errorf("GetTopics: groupId %d: %w", groupId, err)
+	}
+	return topics, total, nil
+}
+
+// GetTopicMessages returns the messages of topicId in the order they were posted
+// https://groups.io/api#get-messages
+func (c *GroupsClient) GetTopicMessages(topicId int) ([]Message, int, error) {
+	msgs, total, err := getAllPages[Message](c, Sprintf("/api/v1/getmessages?topic_id=%d&sort_dir=asc", topicId))
+	if err != nil {
+		return nil, total, Errorf("GetTopicMessages: topicId %d: %w", topicId, err)
+	}
+	return msgs, total, nil
+}
+
+// GetGroupMessages returns every message in the archives of groupId in the order they were posted
+// https://groups.io/api#get-messages
+func (c *GroupsClient) GetGroupMessages(groupId int) ([]Message, int, error) {
+	msgs, total, err := getAllPages[Message](c, Sprintf("/api/v1/getmessages?group_id=%d&sort_dir=asc", groupId))
+	if err != nil {
+		return nil, total, Errorf("GetGroupMessages: groupId %d: %w", groupId, err)
+	}
+	return msgs, total, nil
+}
+
+// GetGroupMessagesBefore returns the messages in the archives of groupId posted before before, oldest first, without
+// fetching the pages of later messages
+func (c *GroupsClient) GetGroupMessagesBefore(groupId int, before time.Time) ([]Message, int, error) {
+	msgs, total, err := getPagesUntil(c, Sprintf("/api/v1/getmessages?group_id=%d&sort_dir=asc", groupId),
+		func(msg Message) bool {
+			created, err := msg.CreatedTime()
+			return err == nil && !created.Before(before)
+		})
+	if err != nil {
+		return nil, total, Errorf("GetGroupMessagesBefore: groupId %d: %w", groupId, err)
+	}
+	return msgs, total, nil
+}
+
+// SearchArchives returns the messages in the archives of groupId that match query
+// https://groups.io/api#search-archives
+func (c *GroupsClient) SearchArchives(groupId int, query string) ([]Message, int, error) {
+	endpoint := Sprintf("/api/v1/searcharchives?group_id=%d&q=%s", groupId, url.QueryEscape(query))
+	msgs, total, err := getAllPages[Message](c, endpoint)
+	if err != nil {
+		return nil, total, Errorf("SearchArchives: groupId %d %q: %w", groupId, query, err)
+	}
+	return msgs, total, nil
+}
+
+// ArchiveFilter selects the messages to export from a group's archives, zero fields match everything. After is
+// inclusive and Before exclusive.
+type ArchiveFilter struct {
+	After   time.Time
+	Before  time.Time
+	Hashtag string
+	Query   string
+}
+
+// matchTime reports whether msg was posted within the date range of f
+func (f ArchiveFilter) matchTime(msg Message) bool {
+	if f.After.IsZero() && f.Before.IsZero() {
+		return true
+	}
+	created, err := msg.CreatedTime()
+	if err != nil {
+		return false
+	}
+	if !f.After.IsZero() && created.Before(f.After) {
+		return false
+	}
+	if !f.Before.IsZero() && !created.Before(f.Before) {
+		return false
+	}
+	return true
+}
+
+// GetArchive returns the messages in the archives of groupId selected by f. Only the topics tagged with f.Hashtag
+// are read when it is set, and only the search results for f.Query when that is set. Otherwise the archive is read
+// oldest first and reading stops at f.Before.
+func (c *GroupsClient) GetArchive(groupId int, f ArchiveFilter) ([]Message, error) {
+	var candidates []Message
+	switch {
+	case f.Query != "":
+		msgs, _, err := c.SearchArchives(groupId, f.Query)
+		if err != nil {
+			return nil, err
+		}
+		if f.Hashtag != "" {
+			topics, _, err := c.GetTopics(groupId)
+			if err != nil {
+				return nil, err
+			}
+			tagged := make(map[int]bool)
+			for _, topic := range topics {
+				tagged[topic.ID] = topic.HasHashtag(f.Hashtag)
+			}
+			for _, msg := range msgs {
+				if tagged[msg.TopicID] {
+					candidates = append(candidates, msg)
+				}
+			}
+		} else {
+			candidates = msgs
+		}
+	case f.Hashtag != "":
+		topics, _, err := c.GetTopics(groupId)
+		if err != nil {
+			return nil, err
+		}
+		for _, topic := range topics {
+			if !topic.HasHashtag(f.Hashtag) {
+				continue
+			}
+			msgs, _, err := c.GetTopicMessages(topic.ID)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, msgs...)
+		}
+	case !f.Before.IsZero():
+		msgs, _, err := c.GetGroupMessagesBefore(groupId, f.Before)
+		if err != nil {
+			return nil, err
+		}
+		candidates = msgs
+	default:
+		msgs, _, err := c.GetGroupMessages(groupId)
+		if err != nil {
+			return nil, err
+		}
+		candidates = msgs
+	}
+
+	selected := make([]Message, 0, len(candidates))
+	for _, msg := range candidates {
+		if f.matchTime(msg) {
+			selected = append(selected, msg)
+		}
+	}
+	return selected, nil
+}
+
+// RFC822 renders msg as an RFC 5322 message, groupEmail identifies the list it was posted to
+func (m Message) RFC822(groupEmail string) []byte {
+	var b bytes.Buffer
+	from := m.Email
+	if from == "" {
+		from = groupEmail
+	}
+	created, err := m.CreatedTime()
+	if err != nil {
+		created = time.Unix(0, 0).UTC()
+	}
+	contentType := "text/html"
+	if m.IsPlainText {
+		contentType = "text/plain"
+	}
+	Fprintf(&b, "From: %s <%s>\r\n", mime.QEncoding.Encode("utf-8", m.Name), from)
+	Fprintf(&b, "To: %s\r\n", groupEmail)
+	Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	Fprintf(&b, "Date: %s\r\n", created.Format(time.RFC1123Z))
+	Fprintf(&b, "Message-ID: <%d.%d@groups.io>\r\n", m.GroupID, m.ID)
+	if m.ReplyTo != "" {
+		Fprintf(&b, "Reply-To: %s\r\n", m.ReplyTo)
+	}
+	Fprintf(&b, "X-Groupsio-Topic-Id: %d\r\n", m.TopicID)
+	Fprintf(&b, "X-Groupsio-Msg-Num: %d\r\n", m.MsgNum)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n"))
+	if !strings.HasSuffix(m.Body, "\n") {
+		b.WriteString("\r\n")
+	}
+	return b.Bytes()
+}
+
+// WriteMbox writes msgs to w in mboxrd format, escaping body lines that start with any number of > followed by From
+func WriteMbox(w io.Writer, msgs []Message, groupEmail string) error {
+	for _, msg := range msgs {
+		created, err := msg.CreatedTime()
+		if err != nil {
+			created = time.Unix(0, 0).UTC()
+		}
+		sender := msg.Email
+		if sender == "" {
+			sender = groupEmail
+		}
+		if _, err := Fprintf(w, "From %s %s\n", sender, created.UTC().Format(time.ANSIC)); err != nil {
+			return err
+		}
+		raw := strings.ReplaceAll(string(msg.RFC822(groupEmail)), "\r\n", "\n")
+		for _, line := range strings.SplitAfter(raw, "\n") {
+			if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+				line = ">" + line
+			}
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMaildir writes each of msgs as a file in the cur directory of the Maildir at dir, creating it if needed.
+// Files are named after the message so that exporting again overwrites rather than duplicates them.
+func WriteMaildir(dir string, msgs []Message, groupEmail string) error {
+	for _, sub := range []string{"cur", "new", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return err
+		}
+	}
+	for _, msg := range msgs {
+		created, err := msg.CreatedTime()
+		if err != nil {
+			created = time.Unix(0, 0).UTC()
+		}
+		name := Sprintf("%d.%d_%d.groups-admin", created.Unix(), msg.GroupID, msg.ID)
+		tmp := filepath.Join(dir, "tmp", name)
+		if err := os.WriteFile(tmp, msg.RFC822(groupEmail), 0o600); err != nil {
+			return err
+		}
+		// :2,S marks the message as seen
+		if err := os.Rename(tmp, filepath.Join(dir, "cur", name+":2,S")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSONLines writes each of msgs to w as a JSON object on its own line
+func WriteJSONLines(w io.Writer, msgs []Message) error {
+	enc := json.NewEncoder(w)
+	for _, msg := range msgs {
+		if err := enc.Encode(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetArchiveStopsPagingAtBefore(t *testing.T) {
+	pages := [][]Message{
+		{{ID: 1, Created: "2024-01-10T00:00:00Z"}, {ID: 2, Created: "2024-02-03T00:00:00Z"}},
+		{{ID: 3, Created: "2024-02-20T00:00:00Z"}, {ID: 4, Created: "2024-03-01T00:00:00Z"}},
+		{{ID: 5, Created: "2024-04-01T00:00:00Z"}},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 0
+		if token := r.URL.Query().Get("page_token"); token != "" {
+			page = int(token[0] - '0')
+		}
+		requests++
+		json.NewEncoder(w).Encode(listPage[Message]{Data: pages[page], HasMore: page < len(pages)-1, NextPageToken: page + 1})
+	}))
+	defer server.Close()
+
+	c := &GroupsClient{BaseURL: server.URL, Client: server.Client()}
+	msgs, err := c.GetArchive(1, ArchiveFilter{
+		After:  time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Before: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("GetArchive() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("GetArchive() made %d requests, want 2", requests)
+	}
+	if len(msgs) != 2 || msgs[0].ID != 2 || msgs[1].ID != 3 {
+		t.Errorf("GetArchive() = %+v, want messages 2 and 3", msgs)
+	}
+}
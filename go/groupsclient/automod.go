@@ -0,0 +1,199 @@
+package groupsclient
+
+import (
+	"bytes"
+	"encoding/json"
+	. "fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ModerationDecision is the outcome of evaluating ModerationRules against a PendingMsg
+type ModerationDecision string
+
+const (
+	DecisionApprove ModerationDecision = "approve"
+	DecisionReject  ModerationDecision = "reject"
+	DecisionHold    ModerationDecision = "hold"
+)
+
+// ModerationRule decides what to do with a pending message. Every condition that is set must match for the rule to
+// match, a list condition matches when any one of its entries does.
+type ModerationRule struct {
+	Name     string             `json:"name"`
+	Decision ModerationDecision `json:"decision"`
+	// RejectReason is sent to the sender when Decision is reject
+	RejectReason string `json:"reject_reason,omitempty"`
+
+	SenderEmails   []string `json:"sender_emails,omitempty"`
+	SenderDomains  []string `json:"sender_domains,omitempty"`
+	SubjectRegexes []string `json:"subject_regexes,omitempty"`
+	HasAttachments *bool    `json:"has_attachments,omitempty"`
+	HasVirus       *bool    `json:"has_virus,omitempty"`
+	IsWebPost      *bool    `json:"is_web_post,omitempty"`
+	// Headers maps a header name, e.g. List-Id or X-Mailer, to a regex its decoded value in the raw message must match
+	Headers map[string]string `json:"headers,omitempty"`
+
+	subjectRes []*regexp.Regexp
+	headerRes  map[string]*regexp.Regexp
+}
+
+// ModerationRules is an ordered list of rules, the first rule to match a message decides what happens to it.
+// Messages that no rule matches are held.
+type ModerationRules struct {
+	Rules []ModerationRule `json:"rules"`
+}
+
+// ModerationResult is the decision reached for Msg and the name of the rule that reached it
+type ModerationResult struct {
+	Msg          PendingMsg
+	Decision     ModerationDecision
+	Rule         string
+	RejectReason string
+}
+
+// LoadModerationRules reads and validates the JSON rules file at path
+func LoadModerationRules(path string) (*ModerationRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules ModerationRules
+	// a misspelt condition would otherwise be dropped silently, leaving a rule that matches more than intended
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return nil, Errorf("LoadModerationRules: %s: %w", path, err)
+	}
+	if err := rules.compile(); err != nil {
+		return nil, Errorf("LoadModerationRules: %s: %w", path, err)
+	}
+	return &rules, nil
+}
+
+// compile validates each rule and compiles its subject regexes
+func (r *ModerationRules) compile() error {
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.Name == "" {
+			rule.Name = Sprintf("rule %d", i+1)
+		}
+		switch rule.Decision {
+		case DecisionApprove, DecisionReject, DecisionHold:
+		default:
+			return Errorf("%s: unknown decision %q", rule.Name, rule.Decision)
+		}
+		if !rule.hasConditions() {
+			return Errorf("%s: has no conditions, it would match every message", rule.Name)
+		}
+		rule.subjectRes = make([]*regexp.Regexp, 0, len(rule.SubjectRegexes))
+		for _, expr := range rule.SubjectRegexes {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return Errorf("%s: subject regex %q: %w", rule.Name, expr, err)
+			}
+			rule.subjectRes = append(rule.subjectRes, re)
+		}
+		rule.headerRes = make(map[string]*regexp.Regexp, len(rule.Headers))
+		for name, expr := range rule.Headers {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return Errorf("%s: %s header regex %q: %w", rule.Name, name, expr, err)
+			}
+			rule.headerRes[name] = re
+		}
+	}
+	return nil
+}
+
+// hasConditions reports whether any condition is set in rule
+func (rule ModerationRule) hasConditions() bool {
+	return len(rule.SenderEmails) > 0 || len(rule.SenderDomains) > 0 || len(rule.SubjectRegexes) > 0 ||
+		rule.HasAttachments != nil || rule.HasVirus != nil || rule.IsWebPost != nil || len(rule.Headers) > 0
+}
+
+// Matches reports whether every condition set in rule holds for msg, parsed is msg's decoded raw message and may be
+// nil when it could not be parsed, in which case rules with header conditions do not match
+func (rule ModerationRule) Matches(msg PendingMsg, parsed *ParsedMessage) bool {
+	sender := strings.ToLower(msg.SenderEmail)
+	if len(rule.SenderEmails) > 0 && !containsFold(rule.SenderEmails, sender) {
+		return false
+	}
+	if len(rule.SenderDomains) > 0 {
+		_, domain, _ := strings.Cut(sender, "@")
+		if !containsFold(rule.SenderDomains, domain) {
+			return false
+		}
+	}
+	if len(rule.subjectRes) > 0 {
+		matched := false
+		for _, re := range rule.subjectRes {
+			if re.MatchString(msg.Subject) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if rule.HasAttachments != nil && *rule.HasAttachments != msg.HasAttachments {
+		return false
+	}
+	if rule.HasVirus != nil && *rule.HasVirus != (msg.VirusName != "") {
+		return false
+	}
+	if rule.IsWebPost != nil && *rule.IsWebPost != msg.IsWebPost {
+		return false
+	}
+	for name, re := range rule.headerRes {
+		if parsed == nil || !re.MatchString(parsed.HeaderValue(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate returns the decision of the first rule that matches msg, or hold when none do. A message carrying a virus
+// is never approved, it is held instead.
+func (r *ModerationRules) Evaluate(msg PendingMsg) ModerationResult {
+	parsed, err := msg.Parse()
+	if err != nil {
+		parsed = nil
+	}
+	for _, rule := range r.Rules {
+		if rule.Matches(msg, parsed) {
+			if rule.Decision == DecisionApprove && msg.VirusName != "" {
+				return ModerationResult{Msg: msg, Decision: DecisionHold, Rule: rule.Name + " (virus " + msg.VirusName + ")"}
+			}
+			return ModerationResult{Msg: msg, Decision: rule.Decision, Rule: rule.Name, RejectReason: rule.RejectReason}
+		}
+	}
+	return ModerationResult{Msg: msg, Decision: DecisionHold, Rule: "no rule matched"}
+}
+
+// ApplyModerationResult approves or rejects the message in result as decided, held messages are left alone
+func (c *GroupsClient) ApplyModerationResult(result ModerationResult) error {
+	switch result.Decision {
+	case DecisionApprove:
+		if result.Msg.VirusName != "" {
+			return Errorf("ApplyModerationResult: message %d carries %s and is not approved", result.Msg.ID, result.Msg.VirusName)
+		}
+		return c.ApprovePendingMsg(result.Msg)
+	case DecisionReject:
+		return c.RejectPendingMsg(result.Msg, result.RejectReason)
+	default:
+		return nil
+	}
+}
+
+// containsFold reports whether s is in list, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(strings.TrimSpace(entry), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,9 +5,11 @@ import (
 	. "fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -18,12 +20,14 @@ type TokenResponse struct {
 	Token string `json:"token"`
 }
 
-// GroupsClient struct to hold client configuration
+// GroupsClient struct to hold client configuration, the Token is redacted when the client is printed or logged
 type GroupsClient struct {
 	BaseURL string
-	Token   string
+	Token   string `sensitive:"true"`
 	Client  *http.Client
 }
+
+// Org fields tagged sensitive:"true" are redacted when an Org is printed, logged or marshalled, see Redact
 type Org struct {
 	ID                  int    `json:"id"`
 	Object              string `json:"object"`
@@ -41,9 +45,11 @@ type Org struct {
 	NoAccountText       string `json:"no_account_text"`
 	SsoProvider         string `json:"sso_provider"`
 	SsoClientID         string `json:"sso_client_id"`
-	SsoClientSecret     string `json:"sso_client_secret"`
+	SsoClientSecret     string `json:"sso_client_secret" sensitive:"true"`
 	SsoDomain           string `json:"sso_domain"`
 }
+
+// User fields tagged sensitive:"true" are redacted when a User is printed, logged or marshalled, see Redact
 type User struct {
 	ID                      int    `json:"id"`
 	Object                  string `json:"object"`
@@ -60,9 +66,9 @@ type User struct {
 	AllowFacebookLogin      bool   `json:"allow_facebook_login"`
 	AllowGoogleLogin        bool   `json:"allow_google_login"`
 	AllowSsoLogin           bool   `json:"allow_sso_login"`
-	CsrfToken               string `json:"csrf_token"`
+	CsrfToken               string `json:"csrf_token" sensitive:"true"`
 	TwoFactorEnabled        bool   `json:"two_factor_enabled"`
-	RecoveryCodes           string `json:"recovery_codes"`
+	RecoveryCodes           string `json:"recovery_codes" sensitive:"true"`
 	DontMungeMessageID      bool   `json:"dont_munge_message_id"`
 	AboutMe                 string `json:"about_me"`
 	AboutFormat             string `json:"about_format"`
@@ -89,54 +95,54 @@ type User struct {
 	HomePage                string `json:"home_page"`
 }
 type MemberInfo struct {
-	ID                  int       `json:"id"`
-	Object              string    `json:"object"`
-	Created             string    `json:"created"`
-	Updated             string    `json:"updated"`
-	UserID              int       `json:"user_id"`
-	GroupID             int       `json:"group_id"`
-	GroupName           string    `json:"group_name"`
-	Status              string    `json:"status"`
-	PostStatus          string    `json:"post_status"`
-	EmailDelivery       string    `json:"email_delivery"`
-	MessageSelection    string    `json:"message_selection"`
-	AutoFollowReplies   bool      `json:"auto_follow_replies"`
-	MaxAttachmentSize   string    `json:"max_attachment_size"`
-	ApprovedPosts       int       `json:"approved_posts"`
-	ModStatus           string    `json:"mod_status"`
-	PendingMsgNotify    string    `json:"pending_msg_notify"`
-	PendingSubNotify    string    `json:"pending_sub_notify"`
-	SubNotify           string    `json:"sub_notify"`
-	StorageNotify       string    `json:"storage_notify"`
-	SubGroupNotify      string    `json:"sub_group_notify"`
-	MessageReportNotify string    `json:"message_report_notify"`
-	AccountNotify       string    `json:"account_notify"`
-	ModPermissions      string    `json:"mod_permissions"`
-	OwnerMsgNotify      string    `json:"owner_msg_notify"`
-	ChatNotify          string    `json:"chat_notify"`
-	PhotoNotify         string    `json:"photo_notify"`
-	FileNotify          string    `json:"file_notify"`
-	WikiNotify          string    `json:"wiki_notify"`
-	DatabaseNotify      string    `json:"database_notify"`
-	Email               string    `json:"email"`
-	UserStatus          string    `json:"user_status"`
-	UserName            string    `json:"user_name"`
-	Timezone            string    `json:"timezone"`
-	FullName            string    `json:"full_name"`
-	AboutMe             string    `json:"about_me"`
-	Location            string    `json:"location"`
-	Website             string    `json:"website"`
-	ProfilePrivacy      string    `json:"profile_privacy"`
-	DontMungeMessageID  bool      `json:"dont_munge_message_id"`
-	UseSignature        bool      `json:"use_signature"`
-	UseSignatureEmail   bool      `json:"use_signature_email"`
-	Signature           string    `json:"signature"`
-	Color               string    `json:"color"`
-	CoverPhotoURL       string    `json:"cover_photo_url"`
-	IconURL             string    `json:"icon_url"`
-	NiceGroupName       string    `json:"nice_group_name"`
-	SubsCount           int       `json:"subs_count"`
-	MostRecentMessage   time.Time `json:"most_recent_message"`
+	ID                  int              `json:"id"`
+	Object              string           `json:"object"`
+	Created             string           `json:"created"`
+	Updated             string           `json:"updated"`
+	UserID              int              `json:"user_id"`
+	GroupID             int              `json:"group_id"`
+	GroupName           string           `json:"group_name"`
+	Status              MemberStatus     `json:"status"`
+	PostStatus          PostStatus       `json:"post_status"`
+	EmailDelivery       EmailDelivery    `json:"email_delivery"`
+	MessageSelection    MessageSelection `json:"message_selection"`
+	AutoFollowReplies   bool             `json:"auto_follow_replies"`
+	MaxAttachmentSize   string           `json:"max_attachment_size"`
+	ApprovedPosts       int              `json:"approved_posts"`
+	ModStatus           ModStatus        `json:"mod_status"`
+	PendingMsgNotify    NotifySetting    `json:"pending_msg_notify"`
+	PendingSubNotify    NotifySetting    `json:"pending_sub_notify"`
+	SubNotify           NotifySetting    `json:"sub_notify"`
+	StorageNotify       NotifySetting    `json:"storage_notify"`
+	SubGroupNotify      NotifySetting    `json:"sub_group_notify"`
+	MessageReportNotify NotifySetting    `json:"message_report_notify"`
+	AccountNotify       NotifySetting    `json:"account_notify"`
+	ModPermissions      string           `json:"mod_permissions"`
+	OwnerMsgNotify      NotifySetting    `json:"owner_msg_notify"`
+	ChatNotify          NotifySetting    `json:"chat_notify"`
+	PhotoNotify         NotifySetting    `json:"photo_notify"`
+	FileNotify          NotifySetting    `json:"file_notify"`
+	WikiNotify          NotifySetting    `json:"wiki_notify"`
+	DatabaseNotify      NotifySetting    `json:"database_notify"`
+	Email               string           `json:"email"`
+	UserStatus          UserStatus       `json:"user_status"`
+	UserName            string           `json:"user_name"`
+	Timezone            string           `json:"timezone"`
+	FullName            string           `json:"full_name"`
+	AboutMe             string           `json:"about_me"`
+	Location            string           `json:"location"`
+	Website             string           `json:"website"`
+	ProfilePrivacy      string           `json:"profile_privacy"`
+	DontMungeMessageID  bool             `json:"dont_munge_message_id"`
+	UseSignature        bool             `json:"use_signature"`
+	UseSignatureEmail   bool             `json:"use_signature_email"`
+	Signature           string           `json:"signature"`
+	Color               string           `json:"color"`
+	CoverPhotoURL       string           `json:"cover_photo_url"`
+	IconURL             string           `json:"icon_url"`
+	NiceGroupName       string           `json:"nice_group_name"`
+	SubsCount           int              `json:"subs_count"`
+	MostRecentMessage   time.Time        `json:"most_recent_message"`
 	Perms               struct {
 		Object                          string `json:"object"`
 		ArchivesVisible                 bool   `json:"archives_visible"`
@@ -189,24 +195,27 @@ type MemberInfo struct {
 		SubPage                         bool   `json:"sub_page"`
 		ModPage                         bool   `json:"mod_page"`
 	} `json:"perms"`
-	ExtraMemberData []struct {
-		ColID          int       `json:"col_id"`
-		ColType        string    `json:"col_type"`
-		Text           string    `json:"text,omitempty"`
-		Checked        bool      `json:"checked,omitempty"`
-		Date           time.Time `json:"date,omitempty"`
-		Time           time.Time `json:"time,omitempty"`
-		StreetAddress1 string    `json:"street_address1,omitempty"`
-		StreetAddress2 string    `json:"street_address2,omitempty"`
-		City           string    `json:"city,omitempty"`
-		State          string    `json:"state,omitempty"`
-		Zip            string    `json:"zip,omitempty"`
-		Country        string    `json:"country,omitempty"`
-		Title          string    `json:"title,omitempty"`
-		URL            string    `json:"url,omitempty"`
-		Desc           string    `json:"desc,omitempty"`
-		ImageName      string    `json:"image_name,omitempty"`
-	} `json:"extra_member_data"`
+	ExtraMemberData []ExtraMemberData `json:"extra_member_data"`
+}
+
+// ExtraMemberData is the member's answer to one of a group's custom member data columns
+type ExtraMemberData struct {
+	ColID          int       `json:"col_id"`
+	ColType        string    `json:"col_type"`
+	Text           string    `json:"text,omitempty"`
+	Checked        bool      `json:"checked,omitempty"`
+	Date           time.Time `json:"date,omitempty"`
+	Time           time.Time `json:"time,omitempty"`
+	StreetAddress1 string    `json:"street_address1,omitempty"`
+	StreetAddress2 string    `json:"street_address2,omitempty"`
+	City           string    `json:"city,omitempty"`
+	State          string    `json:"state,omitempty"`
+	Zip            string    `json:"zip,omitempty"`
+	Country        string    `json:"country,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	URL            string    `json:"url,omitempty"`
+	Desc           string    `json:"desc,omitempty"`
+	ImageName      string    `json:"image_name,omitempty"`
 }
 type MemberInfoList struct {
 	Object        string `json:"object"`
@@ -259,6 +268,8 @@ type PendingMsg struct {
 	EditReason string `json:"edit_reason"`
 	Type       string `json:"type"`
 	VirusName  string `json:"virus_name"`
+	// GroupName is not part of the groups.io object, it is set by GetPendingMsgList
+	GroupName string `json:"group_name,omitempty"`
 }
 
 type PendingMsgList struct {
@@ -330,6 +341,13 @@ func (c *GroupsClient) Authenticate(email, password string) error {
 
 // doRequest method to make authenticated HTTP requests
 func (c *GroupsClient) doRequest(method, endpoint string, body io.Reader) (*http.Response, error) {
+	// FIXME gate this setting for POST reqs only??
+	header := http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}
+	return c.doRequestWithHeader(method, endpoint, header, body)
+}
+
+// doRequestWithHeader makes an authenticated HTTP request with the headers in header, such as its Content-Type
+func (c *GroupsClient) doRequestWithHeader(method, endpoint string, header http.Header, body io.Reader) (*http.Response, error) {
 	time.Sleep(1 * time.Second)
 	req, err := http.NewRequest(method, Sprintf("%s%s", c.BaseURL, endpoint), body)
 	log.Printf("client.doRequest: %s%s\n", c.BaseURL, endpoint)
@@ -339,11 +357,191 @@ func (c *GroupsClient) doRequest(method, endpoint string, body io.Reader) (*http
 
 	// Add the token to the Authorization header using basic auth format
 	req.SetBasicAuth(c.Token, "")
-	// FIXME gate this setting for POST reqs only??
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for name, values := range header {
+		req.Header[name] = values
+	}
 	return c.Client.Do(req)
 }
 
+// postForm POSTs formData to endpoint and, when out is not nil, unmarshals the JSON response into out
+func (c *GroupsClient) postForm(endpoint string, formData url.Values, out interface{}) error {
+	resp, err := c.doRequest("POST", endpoint, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		checkClose(resp.Body.Close(), Sprintf("GroupsClient.postForm(%s) Error closing resp.Body", endpoint))
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Errorf("%s: received non-200 response code: %d, responseBody: %s", endpoint, resp.StatusCode, body)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+// getJSON GETs endpoint and unmarshals the JSON response into out
+func (c *GroupsClient) getJSON(endpoint string, out interface{}) error {
+	resp, err := c.doRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		checkClose(resp.Body.Close(), Sprintf("GroupsClient.getJSON(%s) Error closing resp.Body", endpoint))
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Errorf("%s: received non-200 response code: %d, responseBody: %s", endpoint, resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// postMultipart POSTs fields and the files at the paths in files, each as a part named fileField, to endpoint as
+// multipart/form-data and, when out is not nil, unmarshals the JSON response into out. The files are streamed as the
+// request is sent rather than held in memory.
+func (c *GroupsClient) postMultipart(endpoint string, fields url.Values, fileField string, files []string, out interface{}) error {
+	// catch missing files before starting a request that would have to be abandoned part way through
+	for _, path := range files {
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+	}
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, fields, fileField, files))
+	}()
+
+	header := http.Header{"Content-Type": {mw.FormDataContentType()}}
+	resp, err := c.doRequestWithHeader("POST", endpoint, header, pr)
+	// unblocks the writer when the request ended without reading the whole body
+	pr.Close()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		checkClose(resp.Body.Close(), Sprintf("GroupsClient.postMultipart(%s) Error closing resp.Body", endpoint))
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Errorf("%s: received non-200 response code: %d, responseBody: %s", endpoint, resp.StatusCode, body)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+// writeMultipart writes fields and then the files at the paths in files, each as a part named fileField, to mw and
+// closes it
+func writeMultipart(mw *multipart.Writer, fields url.Values, fileField string, files []string) error {
+	for name, values := range fields {
+		for _, value := range values {
+			if err := mw.WriteField(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, path := range files {
+		if err := writeFilePart(mw, fileField, path); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// writeFilePart copies the file at path into a part of mw named field
+func writeFilePart(mw *multipart.Writer, field string, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	part, err := mw.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, f)
+	return err
+}
+
+// listPage is one page of a groups.io list response whose items are in its data field
+// https://groups.io/api#pagination
+type listPage[T any] struct {
+	TotalCount    int  `json:"total_count"`
+	HasMore       bool `json:"has_more"`
+	NextPageToken int  `json:"next_page_token"`
+	Data          []T  `json:"data"`
+}
+
+// getAllPages GETs endpoint, which must already have a query string, following next_page_token until every item of
+// the list has been fetched. Returns the items and the total count reported by groups.io.
+func getAllPages[T any](c *GroupsClient, endpoint string) ([]T, int, error) {
+	return getPagesUntil[T](c, endpoint, nil)
+}
+
+// getPagesUntil is getAllPages but stops, without requesting any further pages, at the first item stop returns true
+// for. That item and those after it are not returned. A nil stop fetches every item.
+func getPagesUntil[T any](c *GroupsClient, endpoint string, stop func(item T) bool) ([]T, int, error) {
+	objectLimit := 100
+	items := make([]T, 0)
+	totalCount := 0
+	pageToken := 0
+	for {
+		pageEndpoint := Sprintf("%s&limit=%d", endpoint, objectLimit)
+		if pageToken != 0 {
+			pageEndpoint = Sprintf("%s&page_token=%d", pageEndpoint, pageToken)
+		}
+		resp, err := c.doRequest("GET", pageEndpoint, nil)
+		if err != nil {
+			return nil, totalCount, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		checkClose(resp.Body.Close(), Sprintf("GroupsClient.getPagesUntil(%s) Error closing resp.Body", endpoint))
+		if err != nil {
+			return nil, totalCount, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, totalCount, Errorf("%s: received non-200 response code: %d, responseBody: %s", endpoint, resp.StatusCode, body)
+		}
+
+		var page listPage[T]
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, totalCount, err
+		}
+		totalCount = page.TotalCount
+		for i, item := range page.Data {
+			if stop != nil && stop(item) {
+				return append(items, page.Data[:i]...), totalCount, nil
+			}
+		}
+		items = append(items, page.Data...)
+		if !page.HasMore {
+			return items, totalCount, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
 // GetOrg gets the org object for domain that the client is authenticated against
 // https://groups.io/api#get_org
 // https://groups.io/api#the-org-object
@@ -435,31 +633,30 @@ func (c *GroupsClient) GetMemberInfoList() ([]MemberInfo, int, error) {
 	return allSubscriptions, subCount, nil
 }
 
-// GetMemberId  returns the membership ID of userId if they are a member of groupId
+// GetGroupMembers returns the MemberInfo of every member of groupId with pagination
 // using https://groups.io/api#getmembers
-func (c *GroupsClient) GetMemberId(groupId int, userId int) (int, error) {
+func (c *GroupsClient) GetGroupMembers(groupId int) ([]MemberInfo, int, error) {
 	// First call should not include the page_token parameter
 	objectLimit := 100
 	memberCount := 0
 	resp, err := c.doRequest("GET", Sprintf("/api/v1/getmembers?group_id=%d&limit=%d", groupId, objectLimit), nil)
 
 	if err != nil {
-		return 0, err
+		return nil, memberCount, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		log.Fatalf("GetMemberId,  getmembers, resp: %v, groupId %d, (userId %d)", resp, groupId, userId)
-		return 0, nil
+		return nil, memberCount, Errorf("GetGroupMembers: first call to getmembers for groupId %d, received non-200 response code: %d", groupId, resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, err
+		return nil, memberCount, err
 	}
-	checkClose(resp.Body.Close(), "GroupsClient.GetMemberId() Error closing resp.Body")
+	checkClose(resp.Body.Close(), "GroupsClient.GetGroupMembers() Error closing resp.Body")
 	var memberInfoList MemberInfoList
 	if err := json.Unmarshal(body, &memberInfoList); err != nil {
-		return 0, err
+		return nil, memberCount, err
 	}
 
 	memberCount = memberInfoList.TotalCount
@@ -470,30 +667,40 @@ func (c *GroupsClient) GetMemberId(groupId int, userId int) (int, error) {
 
 	// ref https://groups.io/api#pagination
 	for hasMore != false {
-		endpoint := Sprintf("/api/v1/getmemberss?limit=100&page_token=%d", nextPageToken)
+		endpoint := Sprintf("/api/v1/getmembers?group_id=%d&limit=%d&page_token=%d", groupId, objectLimit, nextPageToken)
 		forLoopResponse, err := c.doRequest("GET", endpoint, nil)
 
 		if err != nil {
-			return 0, err
+			return nil, memberCount, err
 		}
 
 		if forLoopResponse.StatusCode != http.StatusOK {
-			return 0, Errorf("received non-200 response code: %d", forLoopResponse.StatusCode)
+			return nil, memberCount, Errorf("received non-200 response code: %d", forLoopResponse.StatusCode)
 		}
 		body, err := io.ReadAll(forLoopResponse.Body)
 		if err != nil {
-			return 0, err
+			return nil, memberCount, err
 		}
 
 		var members MemberInfoList
 		if err = json.Unmarshal(body, &members); err != nil {
-			return 0, err
+			return nil, memberCount, err
 		}
 
 		allMembers = append(allMembers, members.Data[:]...)
 		nextPageToken = members.NextPageToken
 		hasMore = members.HasMore
-		checkClose(forLoopResponse.Body.Close(), "GroupsClient.GetOrg() Error closing forLoopResponse.Body")
+		checkClose(forLoopResponse.Body.Close(), "GroupsClient.GetGroupMembers() Error closing forLoopResponse.Body")
+	}
+	return allMembers, memberCount, nil
+}
+
+// GetMemberId  returns the membership ID of userId if they are a member of groupId
+// using https://groups.io/api#getmembers
+func (c *GroupsClient) GetMemberId(groupId int, userId int) (int, error) {
+	allMembers, _, err := c.GetGroupMembers(groupId)
+	if err != nil {
+		return 0, err
 	}
 	for _, member := range allMembers {
 		if member.UserID == userId {
@@ -583,75 +790,48 @@ func (c *GroupsClient) GrantOwnerPermsToGroupMember(newOwner MemberInfo, targetG
 	for _, group := range targetGroups {
 		thisGroupsMemberId, gmiError := c.GetMemberId(group.GroupID, newOwner.UserID)
 		if gmiError == nil {
-			m, ugmError := c.UpdateGroupMember(group.GroupID, thisGroupsMemberId, "mod_status", "sub_modstatus_owner")
-			if ugmError == nil {
+			m, ugmError := c.UpdateGroupMember(group.GroupID, thisGroupsMemberId, MemberUpdate{ModStatus: ModStatusOwner})
+			if ugmError == nil && m.IsOwner() {
 				groupsUpdated++
-				log.Printf("INFO Member %s should now be an owner on group %d", m.FullName, group.GroupName)
+				log.Printf("INFO Member %s should now be an owner on group %s", m.FullName, group.GroupName)
 			} else {
 				log.Printf("WARN Member %s was not updated to owner of group %s", newOwner.FullName, group.GroupName)
 			}
 		} else {
-			log.Printf("WARN : Member %s was not a member of group %s GetMemberId returned", newOwner.FullName, group.GroupName, gmiError)
+			log.Printf("WARN : Member %s was not a member of group %s GetMemberId returned %v", newOwner.FullName, group.GroupName, gmiError)
 		}
 	}
 	return groupsUpdated, err
 }
 
-// UpdateGroupMember updates field to value for memberId on groupID, returns an err if this fails to happen
-func (c *GroupsClient) UpdateGroupMember(groupId int, memberId int, field string, value string) (MemberInfo, error) {
+// UpdateGroupMember applies every field set in update to memberId on groupID in a single updatemember call,
+// returns the updated MemberInfo or an err if this fails to happen
+// https://groups.io/api#update-member
+func (c *GroupsClient) UpdateGroupMember(groupId int, memberId int, update MemberUpdate) (MemberInfo, error) {
 	mbr := MemberInfo{}
-	formData := url.Values{}
+	if err := update.Validate(); err != nil {
+		return mbr, Errorf("UpdateGroupMember: groupId %d, memberId %d: %w", groupId, memberId, err)
+	}
+	formData, err := update.formValues()
+	if err != nil {
+		return mbr, Errorf("UpdateGroupMember: groupId %d, memberId %d: %w", groupId, memberId, err)
+	}
 	formData.Set("group_id", strconv.Itoa(groupId))
 	formData.Set("member_info_id", strconv.Itoa(memberId))
 	formData.Set("extra", "true")
-	formData.Set(field, value)
-	reqBody := strings.NewReader(formData.Encode())
-	resp, reqErr := c.doRequest("POST", "/api/v1/updatemember", reqBody)
-	if reqErr != nil {
-		Printf(
-			"UpdateGroupMember: ERROR, endpoint: %s, formData: %v, reqErr %v",
-			"/api/v1/updatemember",
-			formData,
-			reqErr,
-		)
-		Printf("UpdateGroupMember: ERROR, response: %+v, err: %+v", resp, reqErr)
-		os.Exit(1)
-		return mbr, reqErr
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		errRespBody, _ := io.ReadAll(resp.Body)
-		return mbr,
-			Errorf("UpdateGroupMember: ERROR, called : %s, formData : %+v response: %+v, err: %+v responseBody: %s",
-				"/api/v1/updatemember ", formData, resp, reqErr, errRespBody)
-	}
-
-	response, readErr := io.ReadAll(resp.Body)
-	if readErr != nil {
-		return mbr, readErr
-	}
-
-	if unmErr := json.Unmarshal(response, &mbr); unmErr != nil {
-		return mbr, unmErr
+	if err := c.postForm("/api/v1/updatemember", formData, &mbr); err != nil {
+		return mbr, Errorf("UpdateGroupMember: ERROR, formData : %+v, err: %w", formData, err)
 	}
-
 	return mbr, nil
 }
 
-// GetPendingMsgList method to get pending msg info list accessible to the authenticated user with pagination
-// FIRST PASS, see if we can get all the pending messages by passing in the parent group ID from the Org
-// https://groups.io/api#get-
-func (c *GroupsClient) GetPendingMsgList() ([]PendingMsg, int, error) {
-
-	org, err := c.GetOrg()
-	if err != nil {
-		return nil, 0, err
-	}
-	log.Printf("GetPendingMsgList: org %+v\n", org)
+// GetGroupPendingMsgList method to get the pending msg list of groupId with pagination
+// https://groups.io/api#get-pending-messages
+func (c *GroupsClient) GetGroupPendingMsgList(groupId int) ([]PendingMsg, int, error) {
 	v1Endpoint := "getpendingmessages"
 	objectLimit := 100
 	count := 0
-	resource := Sprintf("/api/v1/%s?limit=%d&group_id=%d", v1Endpoint, objectLimit, org.ParentGroupID)
+	resource := Sprintf("/api/v1/%s?limit=%d&group_id=%d", v1Endpoint, objectLimit, groupId)
 	resp, err := c.doRequest("GET", resource, nil)
 
 	if err != nil {
@@ -659,7 +839,7 @@ func (c *GroupsClient) GetPendingMsgList() ([]PendingMsg, int, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, count, Errorf("GetPendingMsgList: first call to %s, received non-200 response code: %d", v1Endpoint, resp.StatusCode)
+		return nil, count, Errorf("GetGroupPendingMsgList: first call to %s, received non-200 response code: %d", v1Endpoint, resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -671,7 +851,7 @@ func (c *GroupsClient) GetPendingMsgList() ([]PendingMsg, int, error) {
 	if err := json.Unmarshal(body, &pendingMsgList); err != nil {
 		return nil, 0, err
 	}
-	checkClose(resp.Body.Close(), "GroupsClient.GetPendingMsgList() Error closing resp.Body")
+	checkClose(resp.Body.Close(), "GroupsClient.GetGroupPendingMsgList() Error closing resp.Body")
 	count = pendingMsgList.TotalCount
 	allPendingMsgs := make([]PendingMsg, 0, count)
 	allPendingMsgs = append(allPendingMsgs, pendingMsgList.Data[:]...)
@@ -680,7 +860,7 @@ func (c *GroupsClient) GetPendingMsgList() ([]PendingMsg, int, error) {
 
 	// ref https://groups.io/api#pagination
 	for hasMore != false {
-		endpoint := Sprintf("/api/v1/%s?limit=100&page_token=%d", v1Endpoint, nextPageToken)
+		endpoint := Sprintf("/api/v1/%s?limit=%d&group_id=%d&page_token=%d", v1Endpoint, objectLimit, groupId, nextPageToken)
 		forLoopResponse, err := c.doRequest("GET", endpoint, nil)
 
 		if err != nil {
@@ -688,7 +868,7 @@ func (c *GroupsClient) GetPendingMsgList() ([]PendingMsg, int, error) {
 		}
 
 		if forLoopResponse.StatusCode != http.StatusOK {
-			return nil, count, Errorf("GroupsClient.GetPendingMsgList() for loop received non-200 response code: %d", forLoopResponse.StatusCode)
+			return nil, count, Errorf("GroupsClient.GetGroupPendingMsgList() for loop received non-200 response code: %d", forLoopResponse.StatusCode)
 		}
 
 		body, err := io.ReadAll(forLoopResponse.Body)
@@ -704,7 +884,7 @@ func (c *GroupsClient) GetPendingMsgList() ([]PendingMsg, int, error) {
 		allPendingMsgs = append(allPendingMsgs, thisPagesPendingMsgList.Data[:]...)
 		nextPageToken = thisPagesPendingMsgList.NextPageToken
 		hasMore = thisPagesPendingMsgList.HasMore
-		checkClose(forLoopResponse.Body.Close(), "GroupsClient.GetPendingMsgList() Error closing forLoopResponse.Body")
+		checkClose(forLoopResponse.Body.Close(), "GroupsClient.GetGroupPendingMsgList() Error closing forLoopResponse.Body")
 
 	}
 
@@ -0,0 +1,58 @@
+package groupsclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPostMultipartStreamsFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "minutes.txt")
+	content := strings.Repeat("minutes of the meeting\n", 10000)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != -1 {
+			t.Errorf("ContentLength = %d, want a streamed body", r.ContentLength)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm() error = %v", err)
+			return
+		}
+		if got := r.FormValue("group_id"); got != "7" {
+			t.Errorf("group_id = %q, want 7", got)
+		}
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("FormFile() error = %v", err)
+			return
+		}
+		defer f.Close()
+		got, _ := io.ReadAll(f)
+		if header.Filename != "minutes.txt" || string(got) != content {
+			t.Errorf("file %s of %d bytes, want minutes.txt of %d bytes", header.Filename, len(got), len(content))
+		}
+		w.Write([]byte(`{"id":3}`))
+	}))
+	defer server.Close()
+
+	c := &GroupsClient{BaseURL: server.URL, Client: server.Client()}
+	var out struct {
+		ID int `json:"id"`
+	}
+	if err := c.postMultipart("/upload", url.Values{"group_id": {"7"}}, "file", []string{path}, &out); err != nil {
+		t.Fatalf("postMultipart() error = %v", err)
+	}
+	if out.ID != 3 {
+		t.Errorf("postMultipart() decoded id %d, want 3", out.ID)
+	}
+	if err := c.postMultipart("/upload", nil, "file", []string{path + ".missing"}, nil); err == nil {
+		t.Errorf("postMultipart() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,238 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	. "fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DriftKind classifies a difference between a Spec and live state for reporting
+type DriftKind string
+
+const (
+	DriftMissingGroup        DriftKind = "missing-group"
+	DriftUndeclaredGroup     DriftKind = "undeclared-group"
+	DriftSettingMismatch     DriftKind = "setting-mismatch"
+	DriftMissingOwner        DriftKind = "missing-owner"
+	DriftUnexpectedOwner     DriftKind = "unexpected-owner"
+	DriftMissingModerator    DriftKind = "missing-moderator"
+	DriftUnexpectedModerator DriftKind = "unexpected-moderator"
+	DriftMembership          DriftKind = "membership"
+)
+
+// Drift is a single difference between a Spec and live state
+type Drift struct {
+	Kind     DriftKind `json:"kind"`
+	Group    string    `json:"group"`
+	Subject  string    `json:"subject,omitempty"`
+	Expected string    `json:"expected,omitempty"`
+	Actual   string    `json:"actual,omitempty"`
+}
+
+func (d Drift) String() string {
+	switch d.Kind {
+	case DriftMissingGroup, DriftUndeclaredGroup:
+		return Sprintf("%s: %s", d.Group, d.Kind)
+	default:
+		return Sprintf("%s: %s %s: expected %q, actual %q", d.Group, d.Kind, d.Subject, d.Expected, d.Actual)
+	}
+}
+
+// DriftReport lists every Drift found between a Spec and live state, grouped by the group they are in
+type DriftReport struct {
+	Spec   string   `json:"spec"`
+	Groups []string `json:"groups"`
+	Drifts []Drift  `json:"drifts"`
+}
+
+// HasDrift reports whether any differences were found
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Drifts) > 0
+}
+
+// DetectDrift compares spec, read from specName, with the live state of every subgroup of the Org without changing
+// anything
+func (c *GroupsClient) DetectDrift(spec *Spec, specName string) (*DriftReport, error) {
+	declared := make(map[string]bool, len(spec.Groups))
+	for _, gs := range spec.Groups {
+		declared[strings.ToLower(gs.Name)] = true
+	}
+	live, err := c.GetLiveGroups(declared)
+	if err != nil {
+		return nil, err
+	}
+	return NewDriftReport(spec, specName, live), nil
+}
+
+// NewDriftReport classifies the changes DiffSpec finds between spec and live and adds the live groups that spec does
+// not declare
+func NewDriftReport(spec *Spec, specName string, live map[string]*LiveGroup) *DriftReport {
+	report := &DriftReport{Spec: specName, Groups: make([]string, 0, len(live)), Drifts: make([]Drift, 0)}
+	declared := make(map[string]bool, len(spec.Groups))
+	for _, gs := range spec.Groups {
+		declared[strings.ToLower(gs.Name)] = true
+	}
+	names := make(map[string]bool)
+	for _, change := range DiffSpec(spec, live).Changes {
+		report.Drifts = append(report.Drifts, driftOf(change))
+	}
+	for _, gs := range spec.Groups {
+		// drifts carry the name of the live group, which may differ in case from the name in spec
+		if lg, ok := live[strings.ToLower(gs.Name)]; ok {
+			names[lg.Group.Name] = true
+		} else {
+			names[gs.Name] = true
+		}
+	}
+	for key, lg := range live {
+		if !declared[key] {
+			report.Drifts = append(report.Drifts, Drift{Kind: DriftUndeclaredGroup, Group: lg.Group.Name})
+			names[lg.Group.Name] = true
+		}
+	}
+	for name := range names {
+		report.Groups = append(report.Groups, name)
+	}
+	sort.Strings(report.Groups)
+	sort.SliceStable(report.Drifts, func(i, j int) bool { return report.Drifts[i].Group < report.Drifts[j].Group })
+	return report
+}
+
+// driftOf classifies change, a change from DiffSpec, as a Drift
+func driftOf(change Change) Drift {
+	d := Drift{Group: change.Group, Subject: change.Subject, Expected: change.To, Actual: change.From}
+	switch change.Kind {
+	case ChangeCreateGroup:
+		d.Kind, d.Subject, d.Expected = DriftMissingGroup, "", ""
+	case ChangeUpdateSetting:
+		d.Kind = DriftSettingMismatch
+	case ChangeRemoveMember:
+		d.Expected = "not a member"
+		d.Kind = roleDrift(change.From, "", DriftMembership)
+	case ChangeAddMember:
+		d.Actual = "not a member"
+		d.Kind = roleDrift("", change.To, DriftMembership)
+	case ChangeSetRole:
+		d.Kind = roleDrift(change.From, change.To, DriftMembership)
+	default:
+		d.Kind = DriftKind(change.Kind)
+	}
+	return d
+}
+
+// roleDrift classifies a change of role from have to want, missing owners are reported ahead of unexpected ones
+func roleDrift(have string, want string, otherwise DriftKind) DriftKind {
+	switch {
+	case want == RoleOwner:
+		return DriftMissingOwner
+	case have == RoleOwner:
+		return DriftUnexpectedOwner
+	case want == RoleModerator:
+		return DriftMissingModerator
+	case have == RoleModerator:
+		return DriftUnexpectedModerator
+	default:
+		return otherwise
+	}
+}
+
+// driftsIn returns the drifts in r for group
+func (r *DriftReport) driftsIn(group string) []Drift {
+	drifts := make([]Drift, 0)
+	for _, d := range r.Drifts {
+		if strings.EqualFold(d.Group, group) {
+			drifts = append(drifts, d)
+		}
+	}
+	return drifts
+}
+
+// Write encodes r to w as "text", "json" or "junit" XML
+func (r *DriftReport) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "text", "":
+		return r.writeText(w)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "junit", "xml":
+		return r.writeJUnit(w)
+	default:
+		return Errorf("DriftReport.Write: unknown format %q", format)
+	}
+}
+
+func (r *DriftReport) writeText(w io.Writer) error {
+	if !r.HasDrift() {
+		_, err := Fprintf(w, "no drift between %s and groups.io across %d group(s)\n", r.Spec, len(r.Groups))
+		return err
+	}
+	if _, err := Fprintf(w, "%d difference(s) between %s and groups.io\n", len(r.Drifts), r.Spec); err != nil {
+		return err
+	}
+	for _, d := range r.Drifts {
+		if _, err := Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes r as a JUnit XML test suite with a test case per group that fails when the group has drifted,
+// so CI systems can show drift like failing tests
+func (r *DriftReport) writeJUnit(w io.Writer) error {
+	suite := junitTestSuite{Name: "drift " + r.Spec, TestCases: make([]junitTestCase, 0, len(r.Groups))}
+	for _, group := range r.Groups {
+		tc := junitTestCase{Name: group, ClassName: "groups-admin.drift"}
+		for _, d := range r.driftsIn(group) {
+			tc.Failures = append(tc.Failures, junitFailure{Type: string(d.Kind), Message: d.String(), Text: d.String()})
+		}
+		if len(tc.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Tests = len(suite.TestCases)
+	suites := junitTestSuites{Name: "groups-admin", Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,224 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	. "fmt"
+	"strings"
+)
+
+// ModStatus is a member's moderation role within a group
+// https://groups.io/api#the-member-info-object
+type ModStatus string
+
+const (
+	ModStatusNone      ModStatus = "sub_modstatus_none"
+	ModStatusModerator ModStatus = "sub_modstatus_moderator"
+	ModStatusOwner     ModStatus = "sub_modstatus_owner"
+)
+
+// MemberStatus is the state of a member's subscription to a group
+type MemberStatus string
+
+const (
+	MemberStatusNormal          MemberStatus = "sub_status_normal"
+	MemberStatusPendingApproval MemberStatus = "sub_status_pending_approval"
+	MemberStatusNotConfirmed    MemberStatus = "sub_status_not_confirmed"
+	MemberStatusBouncing        MemberStatus = "sub_status_bouncing"
+	MemberStatusBounced         MemberStatus = "sub_status_bounced"
+)
+
+// UserStatus is the state of a member's groups.io account, as opposed to their subscription to one group
+type UserStatus string
+
+const (
+	UserStatusConfirmed   UserStatus = "user_status_confirmed"
+	UserStatusUnconfirmed UserStatus = "user_status_unconfirmed"
+	UserStatusBouncing    UserStatus = "user_status_bouncing"
+	UserStatusBounced     UserStatus = "user_status_bounced"
+)
+
+// PostStatus controls whether a member's posts are held for moderation
+type PostStatus string
+
+const (
+	PostStatusDefault      PostStatus = "sub_post_status_default"
+	PostStatusModerated    PostStatus = "sub_post_status_moderated"
+	PostStatusUnmoderated  PostStatus = "sub_post_status_unmoderated"
+	PostStatusNotAllowed   PostStatus = "sub_post_status_not_allowed"
+	PostStatusNewModerated PostStatus = "sub_post_status_new_user_moderated"
+)
+
+// EmailDelivery is how a member receives messages posted to a group
+type EmailDelivery string
+
+const (
+	EmailDeliverySingle  EmailDelivery = "email_delivery_single"
+	EmailDeliveryDigest  EmailDelivery = "email_delivery_digest"
+	EmailDeliverySummary EmailDelivery = "email_delivery_summary"
+	EmailDeliverySpecial EmailDelivery = "email_delivery_special"
+	EmailDeliveryNone    EmailDelivery = "email_delivery_none"
+)
+
+// MessageSelection is which topics a member receives, all of them or only those they follow
+type MessageSelection string
+
+const (
+	MessageSelectionAll           MessageSelection = "message_selection_all"
+	MessageSelectionFollowingOnly MessageSelection = "message_selection_following_only"
+)
+
+// NotifySetting is the value of the *_notify fields of a MemberInfo, it controls whether a moderator or owner is
+// emailed about a class of group events
+type NotifySetting string
+
+const (
+	NotifyEmail NotifySetting = "notify_email"
+	NotifyNone  NotifySetting = "notify_none"
+)
+
+// enumValues maps the short names accepted on the command line to each known API value of an enum
+var (
+	modStatusValues = map[string]ModStatus{
+		"none":      ModStatusNone,
+		"moderator": ModStatusModerator,
+		"owner":     ModStatusOwner,
+	}
+	memberStatusValues = map[string]MemberStatus{
+		"normal":           MemberStatusNormal,
+		"pending_approval": MemberStatusPendingApproval,
+		"not_confirmed":    MemberStatusNotConfirmed,
+		"bouncing":         MemberStatusBouncing,
+		"bounced":          MemberStatusBounced,
+	}
+	userStatusValues = map[string]UserStatus{
+		"confirmed":   UserStatusConfirmed,
+		"unconfirmed": UserStatusUnconfirmed,
+		"bouncing":    UserStatusBouncing,
+		"bounced":     UserStatusBounced,
+	}
+	postStatusValues = map[string]PostStatus{
+		"default":       PostStatusDefault,
+		"moderated":     PostStatusModerated,
+		"unmoderated":   PostStatusUnmoderated,
+		"not_allowed":   PostStatusNotAllowed,
+		"new_moderated": PostStatusNewModerated,
+	}
+	emailDeliveryValues = map[string]EmailDelivery{
+		"single":  EmailDeliverySingle,
+		"digest":  EmailDeliveryDigest,
+		"summary": EmailDeliverySummary,
+		"special": EmailDeliverySpecial,
+		"none":    EmailDeliveryNone,
+	}
+	messageSelectionValues = map[string]MessageSelection{
+		"all":            MessageSelectionAll,
+		"following_only": MessageSelectionFollowingOnly,
+	}
+	notifySettingValues = map[string]NotifySetting{
+		"email": NotifyEmail,
+		"none":  NotifyNone,
+	}
+)
+
+// parseEnum returns the enum value for s, which may be either a short name from values or a full API value
+func parseEnum[T ~string](kind string, s string, values map[string]T) (T, error) {
+	s = strings.TrimSpace(s)
+	if v, ok := values[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	for _, v := range values {
+		if string(v) == s {
+			return v, nil
+		}
+	}
+	return "", Errorf("unknown %s %q", kind, s)
+}
+
+// enumString returns the short name of v, or v itself when it is not one of the known values
+func enumString[T ~string](v T, values map[string]T) string {
+	for name, known := range values {
+		if v == known {
+			return name
+		}
+	}
+	return string(v)
+}
+
+// unmarshalEnum decodes data into v. groups.io adds new values from time to time so, rather than failing the whole
+// response, any string is kept as is and null decodes to the empty value.
+func unmarshalEnum[T ~string](data []byte, v *T) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Errorf("expected a JSON string, got %s", data)
+	}
+	*v = T(s)
+	return nil
+}
+
+// validEnum reports whether v is one of the API values in values
+func validEnum[T ~string](v T, values map[string]T) bool {
+	for _, known := range values {
+		if v == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseModStatus parses s, either "none", "moderator", "owner" or a full API value, as a ModStatus
+func ParseModStatus(s string) (ModStatus, error) {
+	return parseEnum("mod_status", s, modStatusValues)
+}
+
+// ParsePostStatus parses s, either a short name such as "moderated" or a full API value, as a PostStatus
+func ParsePostStatus(s string) (PostStatus, error) {
+	return parseEnum("post_status", s, postStatusValues)
+}
+
+// ParseEmailDelivery parses s, either a short name such as "digest" or a full API value, as an EmailDelivery
+func ParseEmailDelivery(s string) (EmailDelivery, error) {
+	return parseEnum("email_delivery", s, emailDeliveryValues)
+}
+
+// ParseMessageSelection parses s, either "all", "following_only" or a full API value, as a MessageSelection
+func ParseMessageSelection(s string) (MessageSelection, error) {
+	return parseEnum("message_selection", s, messageSelectionValues)
+}
+
+// ParseNotifySetting parses s, either "email", "none" or a full API value, as a NotifySetting
+func ParseNotifySetting(s string) (NotifySetting, error) {
+	return parseEnum("notify setting", s, notifySettingValues)
+}
+
+// ParseMemberStatus parses s, either a short name such as "bouncing" or a full API value, as a MemberStatus
+func ParseMemberStatus(s string) (MemberStatus, error) {
+	return parseEnum("status", s, memberStatusValues)
+}
+
+func (s ModStatus) Valid() bool        { return validEnum(s, modStatusValues) }
+func (s MemberStatus) Valid() bool     { return validEnum(s, memberStatusValues) }
+func (s UserStatus) Valid() bool       { return validEnum(s, userStatusValues) }
+func (s PostStatus) Valid() bool       { return validEnum(s, postStatusValues) }
+func (d EmailDelivery) Valid() bool    { return validEnum(d, emailDeliveryValues) }
+func (m MessageSelection) Valid() bool { return validEnum(m, messageSelectionValues) }
+func (n NotifySetting) Valid() bool    { return validEnum(n, notifySettingValues) }
+
+func (s ModStatus) String() string        { return enumString(s, modStatusValues) }
+func (s MemberStatus) String() string     { return enumString(s, memberStatusValues) }
+func (s UserStatus) String() string       { return enumString(s, userStatusValues) }
+func (s PostStatus) String() string       { return enumString(s, postStatusValues) }
+func (d EmailDelivery) String() string    { return enumString(d, emailDeliveryValues) }
+func (m MessageSelection) String() string { return enumString(m, messageSelectionValues) }
+func (n NotifySetting) String() string    { return enumString(n, notifySettingValues) }
+
+func (s *ModStatus) UnmarshalJSON(data []byte) error        { return unmarshalEnum(data, s) }
+func (s *MemberStatus) UnmarshalJSON(data []byte) error     { return unmarshalEnum(data, s) }
+func (s *UserStatus) UnmarshalJSON(data []byte) error       { return unmarshalEnum(data, s) }
+func (s *PostStatus) UnmarshalJSON(data []byte) error       { return unmarshalEnum(data, s) }
+func (d *EmailDelivery) UnmarshalJSON(data []byte) error    { return unmarshalEnum(data, d) }
+func (m *MessageSelection) UnmarshalJSON(data []byte) error { return unmarshalEnum(data, m) }
+func (n *NotifySetting) UnmarshalJSON(data []byte) error    { return unmarshalEnum(data, n) }
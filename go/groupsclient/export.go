@@ -0,0 +1,88 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	. "fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportSpec fetches every subgroup of the Org whose name matches include, or every subgroup when include is nil,
+// and returns its live state as a Spec. Plain members are only listed when withMembers is set.
+func (c *GroupsClient) ExportSpec(include func(name string) bool, withMembers bool) (*Spec, error) {
+	groups, _, err := c.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]*LiveGroup, len(groups))
+	for _, group := range groups {
+		if include != nil && !include(group.Name) {
+			continue
+		}
+		members, _, err := c.GetGroupMembers(group.ID)
+		if err != nil {
+			return nil, Errorf("ExportSpec: %s: %w", group.Name, err)
+		}
+		live[strings.ToLower(group.Name)] = &LiveGroup{Group: group, Members: members}
+	}
+	return SpecFromLive(live, withMembers), nil
+}
+
+// SpecFromLive returns the Spec that describes live, ordered so that the same state always produces the same
+// document and a sync of it plans no changes
+func SpecFromLive(live map[string]*LiveGroup, withMembers bool) *Spec {
+	spec := &Spec{Groups: make([]GroupSpec, 0, len(live))}
+	for _, lg := range live {
+		gs := GroupSpec{
+			Name:       lg.Group.Name,
+			Settings:   lg.Group.Settings(),
+			Owners:     []string{},
+			Moderators: []string{},
+		}
+		members := make([]string, 0)
+		for _, member := range lg.Members {
+			email := strings.ToLower(member.Email)
+			switch {
+			case member.IsOwner():
+				gs.Owners = append(gs.Owners, email)
+			case member.IsModerator():
+				gs.Moderators = append(gs.Moderators, email)
+			default:
+				members = append(members, email)
+			}
+		}
+		sort.Strings(gs.Owners)
+		sort.Strings(gs.Moderators)
+		if withMembers {
+			sort.Strings(members)
+			gs.Members = &members
+		}
+		spec.Groups = append(spec.Groups, gs)
+	}
+	sort.Slice(spec.Groups, func(i, j int) bool {
+		return strings.ToLower(spec.Groups[i].Name) < strings.ToLower(spec.Groups[j].Name)
+	})
+	return spec
+}
+
+// Write encodes s to w as "yaml" or "json"
+func (s *Spec) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "yaml", "yml", "":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	default:
+		return Errorf("Spec.Write: unknown format %q", format)
+	}
+}
@@ -0,0 +1,320 @@
+package groupsclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	. "fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileEntry is a file or folder in a group's Files section
+// https://groups.io/api#the-file-object
+type FileEntry struct {
+	ID       int    `json:"id"`
+	Object   string `json:"object"`
+	Created  string `json:"created"`
+	Updated  string `json:"updated"`
+	GroupID  int    `json:"group_id"`
+	FolderID int    `json:"folder_id"`
+	Name     string `json:"name"`
+	Desc     string `json:"desc"`
+	IsFolder bool   `json:"is_folder"`
+	Size     int64  `json:"size"`
+	// Sha256 is the hex encoded checksum of the file's contents, empty when groups.io does not report one
+	Sha256 string `json:"sha256"`
+	// Path is the slash separated path of the entry from the root of the Files section, set by GetFileTree
+	Path string `json:"-"`
+}
+
+// FileAction names an administrative action that can be taken in a group's Files section
+type FileAction string
+
+const (
+	UploadFileAction FileAction = "upload"
+	DeleteFileAction FileAction = "delete"
+)
+
+// Permitted reports whether admin may upload to or delete from the Files section of its group, both need ManageFiles
+func (action FileAction) Permitted(admin MemberInfo) bool {
+	switch action {
+	case UploadFileAction, DeleteFileAction:
+		return admin.Perms.ManageFiles
+	default:
+		return false
+	}
+}
+
+// GetFiles returns the files and folders in folderId of groupId, folderId 0 is the root of the Files section
+// https://groups.io/api#get-files
+func (c *GroupsClient) GetFiles(groupId int, folderId int) ([]FileEntry, error) {
+	entries, _, err := getAllPages[FileEntry](c, Sprintf("/api/v1/getfiles?group_id=%d&folder_id=%d", groupId, folderId))
+	if err != nil {
+		return nil, Errorf("GetFiles: groupId %d folderId %d: %w", groupId, folderId, err)
+	}
+	return entries, nil
+}
+
+// GetFileTree returns every file and folder in the Files section of groupId with its Path set, folders come before
+// their contents
+func (c *GroupsClient) GetFileTree(groupId int) ([]FileEntry, error) {
+	return c.getFileTree(groupId, 0, "")
+}
+
+func (c *GroupsClient) getFileTree(groupId int, folderId int, prefix string) ([]FileEntry, error) {
+	entries, err := c.GetFiles(groupId, folderId)
+	if err != nil {
+		return nil, err
+	}
+	tree := make([]FileEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.Path = path.Join(prefix, entry.Name)
+		tree = append(tree, entry)
+		if entry.IsFolder {
+			contents, err := c.getFileTree(groupId, entry.ID, entry.Path)
+			if err != nil {
+				return nil, err
+			}
+			tree = append(tree, contents...)
+		}
+	}
+	return tree, nil
+}
+
+// FindFile returns the entry at the slash separated filePath in the Files section of groupId, "" being the root
+func (c *GroupsClient) FindFile(groupId int, filePath string) (*FileEntry, error) {
+	filePath = strings.Trim(filePath, "/")
+	if filePath == "" {
+		return &FileEntry{GroupID: groupId, IsFolder: true}, nil
+	}
+	folderId := 0
+	var found *FileEntry
+	for _, name := range strings.Split(filePath, "/") {
+		if found != nil && !found.IsFolder {
+			return nil, Errorf("FindFile: %s is not a folder", found.Path)
+		}
+		entries, err := c.GetFiles(groupId, folderId)
+		if err != nil {
+			return nil, err
+		}
+		found = nil
+		for _, entry := range entries {
+			if entry.Name == name {
+				entry := entry
+				found = &entry
+				break
+			}
+		}
+		if found == nil {
+			return nil, Errorf("FindFile: groupId %d has no %s", groupId, filePath)
+		}
+		folderId = found.ID
+	}
+	found.Path = filePath
+	return found, nil
+}
+
+// UploadFile uploads the local file at localPath into folderId of groupId
+// https://groups.io/api#upload-file
+func (c *GroupsClient) UploadFile(groupId int, folderId int, localPath string) (*FileEntry, error) {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("folder_id", strconv.Itoa(folderId))
+	var entry FileEntry
+	if err := c.postMultipart("/api/v1/uploadfile", formData, "file", []string{localPath}, &entry); err != nil {
+		return nil, Errorf("UploadFile: groupId %d %s: %w", groupId, localPath, err)
+	}
+	return &entry, nil
+}
+
+// DeleteFile deletes fileId, a folder is deleted along with its contents
+// https://groups.io/api#delete-file
+func (c *GroupsClient) DeleteFile(groupId int, fileId int) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("file_id", strconv.Itoa(fileId))
+	if err := c.postForm("/api/v1/deletefile", formData, nil); err != nil {
+		return Errorf("DeleteFile: groupId %d fileId %d: %w", groupId, fileId, err)
+	}
+	return nil
+}
+
+// OpenFileDownload starts downloading the contents of entry from offset bytes in. It returns the body and the offset
+// the body starts at, which is 0 when groups.io does not honour the range request.
+// https://groups.io/api#download-file
+func (c *GroupsClient) OpenFileDownload(entry FileEntry, offset int64) (io.ReadCloser, int64, error) {
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", Sprintf("bytes=%d-", offset))
+	}
+	endpoint := Sprintf("/api/v1/downloadfile?group_id=%d&file_id=%d", entry.GroupID, entry.ID)
+	resp, err := c.doRequestWithHeader("GET", endpoint, header, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, 0, nil
+	case http.StatusPartialContent:
+		return resp.Body, offset, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// nothing left to download, let the size and checksum decide whether what is there is right
+		checkClose(resp.Body.Close(), "GroupsClient.OpenFileDownload() Error closing resp.Body")
+		return io.NopCloser(strings.NewReader("")), offset, nil
+	default:
+		checkClose(resp.Body.Close(), "GroupsClient.OpenFileDownload() Error closing resp.Body")
+		return nil, 0, Errorf("OpenFileDownload: %s: received non-200 response code: %d", entry.Name, resp.StatusCode)
+	}
+}
+
+// DownloadFile writes the contents of entry to w
+func (c *GroupsClient) DownloadFile(entry FileEntry, w io.Writer) error {
+	body, _, err := c.OpenFileDownload(entry, 0)
+	if err != nil {
+		return err
+	}
+	defer func() { checkClose(body.Close(), "GroupsClient.DownloadFile() Error closing body") }()
+	if _, err := io.Copy(w, body); err != nil {
+		return Errorf("DownloadFile: %s: %w", entry.Name, err)
+	}
+	return nil
+}
+
+// MirrorAction is what MirrorFiles did with one file
+type MirrorAction string
+
+const (
+	MirrorSkipped    MirrorAction = "up to date"
+	MirrorDownloaded MirrorAction = "downloaded"
+	MirrorResumed    MirrorAction = "resumed"
+)
+
+// MirrorResult is the outcome of mirroring one file
+type MirrorResult struct {
+	Entry  FileEntry
+	Action MirrorAction
+	Err    error
+}
+
+// partialSuffix is added to the name of a file while it is being downloaded
+const partialSuffix = ".part"
+
+// MirrorFiles downloads every file in the Files section of groupId to the same path under dir. Files already in dir
+// that match are skipped, interrupted downloads are resumed from their .part file, and each download is checked
+// against the size and, when groups.io reports one, the checksum of the file before it takes the file's place.
+func (c *GroupsClient) MirrorFiles(groupId int, dir string) ([]MirrorResult, error) {
+	tree, err := c.GetFileTree(groupId)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]MirrorResult, 0, len(tree))
+	for _, entry := range tree {
+		if !filepath.IsLocal(filepath.FromSlash(entry.Path)) {
+			results = append(results, MirrorResult{Entry: entry, Err: Errorf("%q would be written outside %s", entry.Path, dir)})
+			continue
+		}
+		localPath := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		if entry.IsFolder {
+			if err := os.MkdirAll(localPath, 0o755); err != nil {
+				return results, err
+			}
+			continue
+		}
+		result := MirrorResult{Entry: entry, Action: MirrorSkipped}
+		if VerifyFile(localPath, entry) != nil {
+			result.Action, result.Err = c.mirrorFile(entry, localPath)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// mirrorFile downloads entry to localPath by way of a .part file, resuming from what is already in it
+func (c *GroupsClient) mirrorFile(entry FileEntry, localPath string) (MirrorAction, error) {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return MirrorDownloaded, err
+	}
+	partPath := localPath + partialSuffix
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return MirrorDownloaded, err
+	}
+	action, err := c.downloadPart(entry, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return action, err
+	}
+	if err := VerifyFile(partPath, entry); err != nil {
+		// start again from scratch next time rather than resuming a corrupt download
+		os.Remove(partPath)
+		return action, err
+	}
+	return action, os.Rename(partPath, localPath)
+}
+
+// downloadPart appends what is missing of entry to f, a partial download, starting again from scratch when f is
+// longer than entry or groups.io sends the whole file
+func (c *GroupsClient) downloadPart(entry FileEntry, f *os.File) (MirrorAction, error) {
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return MirrorDownloaded, err
+	}
+	if offset > entry.Size {
+		offset = 0
+	}
+	body, start, err := c.OpenFileDownload(entry, offset)
+	if err != nil {
+		return MirrorDownloaded, err
+	}
+	defer func() { checkClose(body.Close(), "GroupsClient.MirrorFiles() Error closing body") }()
+	action := MirrorDownloaded
+	if start > 0 {
+		action = MirrorResumed
+	}
+	if err := f.Truncate(start); err != nil {
+		return action, err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return action, err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		return action, Errorf("%s: %w", entry.Path, err)
+	}
+	return action, nil
+}
+
+// VerifyFile checks the file at localPath has the size and, when it is known, the checksum of entry
+func VerifyFile(localPath string, entry FileEntry) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	if info.Size() != entry.Size {
+		return Errorf("%s is %d bytes, expected %d", localPath, info.Size(), entry.Size)
+	}
+	if entry.Sha256 == "" {
+		return nil
+	}
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, entry.Sha256) {
+		return Errorf("%s has sha256 %s, expected %s", localPath, sum, entry.Sha256)
+	}
+	return nil
+}
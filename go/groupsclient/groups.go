@@ -0,0 +1,223 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	. "fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Group holds the settings of a group
+// https://groups.io/api#the-group-object
+type Group struct {
+	ID             int    `json:"id"`
+	Object         string `json:"object"`
+	Created        string `json:"created"`
+	Updated        string `json:"updated"`
+	Title          string `json:"title"`
+	Name           string `json:"name"`
+	Alias          string `json:"alias"`
+	Desc           string `json:"desc"`
+	PlainDesc      string `json:"plain_desc"`
+	SubjectTag     string `json:"subject_tag"`
+	Footer         string `json:"footer"`
+	Website        string `json:"website"`
+	Announce       bool   `json:"announce"`
+	Moderated      bool   `json:"moderated"`
+	ModeratedUntil string `json:"moderated_until"`
+	ModNewUsers    bool   `json:"mod_new_users"`
+	ModUnmodUsers  bool   `json:"mod_unmod_users"`
+	// Privacy is one of the group_privacy_* values, e.g. group_privacy_unlisted_public_archives
+	Privacy         string `json:"privacy"`
+	Restricted      bool   `json:"restricted"`
+	ApproveMembers  bool   `json:"approve_members"`
+	RestrictPosts   bool   `json:"restrict_posts"`
+	ReplyTo         string `json:"reply_to"`
+	MembersVisible  string `json:"members_visible"`
+	ArchivesVisible string `json:"archives_visible"`
+	SubsCount       int    `json:"subs_count"`
+	ParentGroupID   int    `json:"parent_group_id"`
+	OrgID           int    `json:"org_id"`
+	OrgDomain       string `json:"org_domain"`
+	NiceGroupName   string `json:"nice_group_name"`
+	Email           string `json:"email"`
+	// Feature toggles, each is one of the access_* values or empty when the feature is disabled
+	CalendarAccess        string `json:"calendar_access"`
+	FilesAccess           string `json:"files_access"`
+	DatabaseAccess        string `json:"database_access"`
+	WikiAccess            string `json:"wiki_access"`
+	PhotosAccess          string `json:"photos_access"`
+	MemberDirectoryAccess string `json:"member_directory_access"`
+	PollsAccess           string `json:"polls_access"`
+	ChatAccess            string `json:"chat_access"`
+	HashtagsAccess        string `json:"hashtags_access"`
+}
+
+// GetGroup gets the settings of groupId
+// https://groups.io/api#get-group
+func (c *GroupsClient) GetGroup(groupId int) (*Group, error) {
+	resp, err := c.doRequest("GET", Sprintf("/api/v1/getgroup?group_id=%d", groupId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errorf("GetGroup : groupId %d, received non-200 response code: %d", groupId, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer checkClose(resp.Body.Close(), "GroupsClient.GetGroup() Error closing resp.Body")
+
+	var group Group
+	if err := json.Unmarshal(body, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// ListGroups returns every subgroup of the Org's parent group with pagination, whether or not the authenticated user
+// is subscribed to it
+// https://groups.io/api#get-subgroups
+func (c *GroupsClient) ListGroups() ([]Group, int, error) {
+	org, err := c.GetOrg()
+	if err != nil {
+		return nil, 0, err
+	}
+	groups, total, err := getAllPages[Group](c, Sprintf("/api/v1/getsubgroups?group_id=%d", org.ParentGroupID))
+	if err != nil {
+		return nil, total, Errorf("ListGroups: %w", err)
+	}
+	return groups, total, nil
+}
+
+// GroupSettings maps creategroup/updategroup parameter names to their values
+type GroupSettings map[string]string
+
+// identitySettings are specific to one group and so are not copied by CloneableSettings
+var identitySettings = []string{"title", "desc", "subject_tag"}
+
+// Settings returns the writable settings of g keyed by their creategroup/updategroup parameter names
+func (g Group) Settings() GroupSettings {
+	return GroupSettings{
+		"title":                   g.Title,
+		"desc":                    g.Desc,
+		"subject_tag":             g.SubjectTag,
+		"footer":                  g.Footer,
+		"website":                 g.Website,
+		"announce":                strconv.FormatBool(g.Announce),
+		"moderated":               strconv.FormatBool(g.Moderated),
+		"mod_new_users":           strconv.FormatBool(g.ModNewUsers),
+		"mod_unmod_users":         strconv.FormatBool(g.ModUnmodUsers),
+		"privacy":                 g.Privacy,
+		"restricted":              strconv.FormatBool(g.Restricted),
+		"approve_members":         strconv.FormatBool(g.ApproveMembers),
+		"restrict_posts":          strconv.FormatBool(g.RestrictPosts),
+		"reply_to":                g.ReplyTo,
+		"members_visible":         g.MembersVisible,
+		"archives_visible":        g.ArchivesVisible,
+		"calendar_access":         g.CalendarAccess,
+		"files_access":            g.FilesAccess,
+		"database_access":         g.DatabaseAccess,
+		"wiki_access":             g.WikiAccess,
+		"photos_access":           g.PhotosAccess,
+		"member_directory_access": g.MemberDirectoryAccess,
+		"polls_access":            g.PollsAccess,
+		"chat_access":             g.ChatAccess,
+		"hashtags_access":         g.HashtagsAccess,
+	}
+}
+
+// CloneableSettings returns the settings of g that can be copied to a new group, leaving out its title, description
+// and subject tag
+func (g Group) CloneableSettings() GroupSettings {
+	settings := g.Settings()
+	for _, name := range identitySettings {
+		delete(settings, name)
+	}
+	return settings
+}
+
+// Validate checks every setting in s is one that Group.Settings knows about
+func (s GroupSettings) Validate() error {
+	known := Group{}.Settings()
+	for name := range s {
+		if _, ok := known[name]; !ok {
+			return Errorf("GroupSettings: unknown setting %q", name)
+		}
+	}
+	return nil
+}
+
+func (s GroupSettings) formValues() url.Values {
+	formData := url.Values{}
+	for name, value := range s {
+		formData.Set(name, value)
+	}
+	return formData
+}
+
+// FindGroup returns the subgroup of the Org called name
+func (c *GroupsClient) FindGroup(name string) (*Group, error) {
+	groups, _, err := c.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if strings.EqualFold(group.Name, name) {
+			return &group, nil
+		}
+	}
+	return nil, Errorf("FindGroup: no subgroup called %q", name)
+}
+
+// CreateGroup creates a subgroup of the Org's parent group called name with settings
+// https://groups.io/api#create-group
+func (c *GroupsClient) CreateGroup(name string, settings GroupSettings) (*Group, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	org, err := c.GetOrg()
+	if err != nil {
+		return nil, err
+	}
+	formData := settings.formValues()
+	formData.Set("parent_group_id", strconv.Itoa(org.ParentGroupID))
+	formData.Set("group_name", name)
+	var group Group
+	if err := c.postForm("/api/v1/creategroup", formData, &group); err != nil {
+		return nil, Errorf("CreateGroup: %s: %w", name, err)
+	}
+	return &group, nil
+}
+
+// UpdateGroup changes the settings of groupId to those in settings, settings not in it are left unchanged
+// https://groups.io/api#update-group
+func (c *GroupsClient) UpdateGroup(groupId int, settings GroupSettings) (*Group, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	formData := settings.formValues()
+	formData.Set("group_id", strconv.Itoa(groupId))
+	var group Group
+	if err := c.postForm("/api/v1/updategroup", formData, &group); err != nil {
+		return nil, Errorf("UpdateGroup: groupId %d: %w", groupId, err)
+	}
+	return &group, nil
+}
+
+// DeleteGroup deletes groupId along with its members, archives and everything else in it
+// https://groups.io/api#delete-group
+func (c *GroupsClient) DeleteGroup(groupId int) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	if err := c.postForm("/api/v1/deletegroup", formData, nil); err != nil {
+		return Errorf("DeleteGroup: groupId %d: %w", groupId, err)
+	}
+	return nil
+}
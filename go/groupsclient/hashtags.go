@@ -0,0 +1,319 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	. "fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Hashtag is a hashtag defined in a group, topics tagged with it take on its properties
+// https://groups.io/api#the-hashtag-object
+type Hashtag struct {
+	ID          int    `json:"id"`
+	Object      string `json:"object"`
+	Created     string `json:"created"`
+	Updated     string `json:"updated"`
+	GroupID     int    `json:"group_id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+	// Moderated holds messages in topics with the hashtag for approval
+	Moderated bool `json:"moderated"`
+	// NoEmail stops messages in topics with the hashtag being emailed to members
+	NoEmail bool `json:"no_email"`
+	// ExpireDays, when not zero, has topics with the hashtag locked that many days after they were started
+	ExpireDays int `json:"expire_days"`
+}
+
+// hashtagColors are the colors groups.io shows hashtags in
+var hashtagColors = []string{"red", "orange", "yellow", "green", "teal", "blue", "purple", "pink", "gray", "black"}
+
+// HashtagAction names an administrative action that can be taken on a group's hashtags
+type HashtagAction string
+
+const (
+	CreateHashtagAction HashtagAction = "create"
+	UpdateHashtagAction HashtagAction = "update"
+	DeleteHashtagAction HashtagAction = "delete"
+)
+
+// Permitted reports whether admin may take action on the hashtags of its group, creating one needs CreateHashtags and
+// changing or deleting one ManageHashtags
+func (action HashtagAction) Permitted(admin MemberInfo) bool {
+	switch action {
+	case CreateHashtagAction:
+		return admin.Perms.CreateHashtags
+	case UpdateHashtagAction, DeleteHashtagAction:
+		return admin.Perms.ManageHashtags
+	default:
+		return false
+	}
+}
+
+// HashtagSettings maps createhashtag/updatehashtag parameter names to their values
+type HashtagSettings map[string]string
+
+// Settings returns the writable properties of h keyed by their createhashtag/updatehashtag parameter names
+func (h Hashtag) Settings() HashtagSettings {
+	return HashtagSettings{
+		"color":       h.Color,
+		"description": h.Description,
+		"moderated":   strconv.FormatBool(h.Moderated),
+		"no_email":    strconv.FormatBool(h.NoEmail),
+		"expire_days": strconv.Itoa(h.ExpireDays),
+	}
+}
+
+// Validate checks every setting in s is a known hashtag property with a valid value
+func (s HashtagSettings) Validate() error {
+	known := Hashtag{}.Settings()
+	for name, value := range s {
+		if _, ok := known[name]; !ok {
+			return Errorf("HashtagSettings: unknown setting %q", name)
+		}
+		switch name {
+		case "color":
+			if value != "" && !containsFold(hashtagColors, value) {
+				return Errorf("HashtagSettings: color %q must be one of %s", value, strings.Join(hashtagColors, ", "))
+			}
+		case "moderated", "no_email":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return Errorf("HashtagSettings: %s must be true or false, not %q", name, value)
+			}
+		case "expire_days":
+			if days, err := strconv.Atoi(value); err != nil || days < 0 {
+				return Errorf("HashtagSettings: expire_days must be a number of days, not %q", value)
+			}
+		}
+	}
+	return nil
+}
+
+// validHashtagName checks name, given with or without its leading #, can be used as a hashtag
+func validHashtagName(name string) error {
+	name = strings.TrimPrefix(name, "#")
+	if name == "" || strings.ContainsAny(name, " \t#") {
+		return Errorf("%q is not a hashtag name", name)
+	}
+	return nil
+}
+
+// GetHashtags returns the hashtags defined in groupId
+// https://groups.io/api#get-hashtags
+func (c *GroupsClient) GetHashtags(groupId int) ([]Hashtag, error) {
+	hashtags, _, err := getAllPages[Hashtag](c, Sprintf("/api/v1/gethashtags?group_id=%d", groupId))
+	if err != nil {
+		return nil, Errorf("GetHashtags: groupId %d: %w", groupId, err)
+	}
+	return hashtags, nil
+}
+
+// FindHashtag returns the hashtag of groupId called name, given with or without its leading #
+func (c *GroupsClient) FindHashtag(groupId int, name string) (*Hashtag, error) {
+	hashtags, err := c.GetHashtags(groupId)
+	if err != nil {
+		return nil, err
+	}
+	for _, hashtag := range hashtags {
+		if strings.EqualFold(hashtag.Name, strings.TrimPrefix(name, "#")) {
+			return &hashtag, nil
+		}
+	}
+	return nil, Errorf("FindHashtag: groupId %d has no hashtag %q", groupId, name)
+}
+
+// CreateHashtag defines the hashtag name in groupId with settings
+// https://groups.io/api#create-hashtag
+func (c *GroupsClient) CreateHashtag(groupId int, name string, settings HashtagSettings) (*Hashtag, error) {
+	if err := validHashtagName(name); err != nil {
+		return nil, Errorf("CreateHashtag: %w", err)
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	formData := GroupSettings(settings).formValues()
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("name", strings.TrimPrefix(name, "#"))
+	var hashtag Hashtag
+	if err := c.postForm("/api/v1/createhashtag", formData, &hashtag); err != nil {
+		return nil, Errorf("CreateHashtag: groupId %d #%s: %w", groupId, name, err)
+	}
+	return &hashtag, nil
+}
+
+// UpdateHashtag changes the properties of hashtagId in groupId to those in settings, settings not in it are left
+// unchanged
+// https://groups.io/api#update-hashtag
+func (c *GroupsClient) UpdateHashtag(groupId int, hashtagId int, settings HashtagSettings) (*Hashtag, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	formData := GroupSettings(settings).formValues()
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("hashtag_id", strconv.Itoa(hashtagId))
+	var hashtag Hashtag
+	if err := c.postForm("/api/v1/updatehashtag", formData, &hashtag); err != nil {
+		return nil, Errorf("UpdateHashtag: groupId %d hashtagId %d: %w", groupId, hashtagId, err)
+	}
+	return &hashtag, nil
+}
+
+// DeleteHashtag removes hashtagId from groupId, topics tagged with it lose the tag
+// https://groups.io/api#delete-hashtag
+func (c *GroupsClient) DeleteHashtag(groupId int, hashtagId int) error {
+	formData := GroupSettings{
+		"group_id":   strconv.Itoa(groupId),
+		"hashtag_id": strconv.Itoa(hashtagId),
+	}.formValues()
+	if err := c.postForm("/api/v1/deletehashtag", formData, nil); err != nil {
+		return Errorf("DeleteHashtag: groupId %d hashtagId %d: %w", groupId, hashtagId, err)
+	}
+	return nil
+}
+
+// HashtagSpec declares a hashtag every group in a HashtagSet should have. Only the settings listed are reconciled.
+type HashtagSpec struct {
+	Name     string          `yaml:"name" json:"name"`
+	Settings HashtagSettings `yaml:"settings,omitempty" json:"settings,omitempty"`
+}
+
+// HashtagSet is the standard set of hashtags read by LoadHashtagSet. Hashtags a group has beyond the set are left
+// alone.
+type HashtagSet struct {
+	Hashtags []HashtagSpec `yaml:"hashtags" json:"hashtags"`
+}
+
+// LoadHashtagSet reads and validates the HashtagSet at path, which is JSON when it has a .json extension and YAML
+// otherwise
+func LoadHashtagSet(path string) (*HashtagSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set HashtagSet
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &set)
+	} else {
+		err = yaml.Unmarshal(data, &set)
+	}
+	if err != nil {
+		return nil, Errorf("LoadHashtagSet: %s: %w", path, err)
+	}
+	if err := set.Validate(); err != nil {
+		return nil, Errorf("LoadHashtagSet: %s: %w", path, err)
+	}
+	return &set, nil
+}
+
+// Validate checks hashtag names are valid and unique and their settings are known
+func (s *HashtagSet) Validate() error {
+	names := make(map[string]bool, len(s.Hashtags))
+	for _, hs := range s.Hashtags {
+		if err := validHashtagName(hs.Name); err != nil {
+			return err
+		}
+		name := strings.ToLower(strings.TrimPrefix(hs.Name, "#"))
+		if names[name] {
+			return Errorf("hashtag %s declared more than once", hs.Name)
+		}
+		names[name] = true
+		if err := hs.Settings.Validate(); err != nil {
+			return Errorf("hashtag %s: %w", hs.Name, err)
+		}
+	}
+	return nil
+}
+
+// HashtagChange is a hashtag to create, or settings of one to update, in a group to make it match a HashtagSet
+type HashtagChange struct {
+	Action    HashtagAction
+	GroupID   int
+	GroupName string
+	Name      string
+	HashtagID int
+	// Settings are those to send, From holds the current value of each when updating
+	Settings HashtagSettings
+	From     HashtagSettings
+}
+
+func (c HashtagChange) String() string {
+	names := make([]string, 0, len(c.Settings))
+	for name := range c.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	changes := make([]string, 0, len(names))
+	for _, name := range names {
+		if c.Action == UpdateHashtagAction {
+			changes = append(changes, Sprintf("%s: %q -> %q", name, c.From[name], c.Settings[name]))
+		} else {
+			changes = append(changes, Sprintf("%s=%q", name, c.Settings[name]))
+		}
+	}
+	return Sprintf("%s: %s #%s %s", c.GroupName, c.Action, c.Name, strings.Join(changes, ", "))
+}
+
+// DiffHashtags returns the changes needed to make the hashtags of group, which currently has existing, match set
+func DiffHashtags(set *HashtagSet, group MemberInfo, existing []Hashtag) []HashtagChange {
+	byName := make(map[string]Hashtag, len(existing))
+	for _, hashtag := range existing {
+		byName[strings.ToLower(hashtag.Name)] = hashtag
+	}
+	changes := make([]HashtagChange, 0)
+	for _, hs := range set.Hashtags {
+		name := strings.TrimPrefix(hs.Name, "#")
+		have, ok := byName[strings.ToLower(name)]
+		if !ok {
+			changes = append(changes, HashtagChange{Action: CreateHashtagAction, GroupID: group.GroupID,
+				GroupName: group.GroupName, Name: name, Settings: hs.Settings})
+			continue
+		}
+		current := have.Settings()
+		differ := HashtagSettings{}
+		for setting, want := range hs.Settings {
+			// groups.io may report a color in a different case to the one it was set in
+			if current[setting] != want && !(setting == "color" && strings.EqualFold(current[setting], want)) {
+				differ[setting] = want
+			}
+		}
+		if len(differ) > 0 {
+			changes = append(changes, HashtagChange{Action: UpdateHashtagAction, GroupID: group.GroupID,
+				GroupName: group.GroupName, Name: have.Name, HashtagID: have.ID, Settings: differ, From: current})
+		}
+	}
+	return changes
+}
+
+// PlanHashtagSync returns the changes needed to make the hashtags of each of groups, subscriptions of the
+// authenticated user, match set
+func (c *GroupsClient) PlanHashtagSync(set *HashtagSet, groups []MemberInfo) ([]HashtagChange, error) {
+	changes := make([]HashtagChange, 0)
+	for _, group := range groups {
+		existing, err := c.GetHashtags(group.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, DiffHashtags(set, group, existing)...)
+	}
+	return changes, nil
+}
+
+// ApplyHashtagChange makes change on groups.io
+func (c *GroupsClient) ApplyHashtagChange(change HashtagChange) error {
+	var err error
+	switch change.Action {
+	case CreateHashtagAction:
+		_, err = c.CreateHashtag(change.GroupID, change.Name, change.Settings)
+	case UpdateHashtagAction:
+		_, err = c.UpdateHashtag(change.GroupID, change.HashtagID, change.Settings)
+	default:
+		err = Errorf("ApplyHashtagChange: unexpected action %q", change.Action)
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+package groupsclient
+
+import "testing"
+
+func TestDiffHashtags(t *testing.T) {
+	group := MemberInfo{GroupID: 1, GroupName: "team"}
+	existing := []Hashtag{{ID: 7, Name: "Release", Color: "#FF0000", Description: "release notes"}}
+	tests := []struct {
+		name     string
+		settings HashtagSettings
+		want     string
+	}{
+		{"unchanged", HashtagSettings{"color": "#FF0000", "description": "release notes"}, ""},
+		{"color differs only in case", HashtagSettings{"color": "#ff0000"}, ""},
+		{"description differs only in case", HashtagSettings{"description": "Release notes"},
+			`team: update #Release description: "release notes" -> "Release notes"`},
+		{"color changed", HashtagSettings{"color": "#00ff00"}, `team: update #Release color: "#FF0000" -> "#00ff00"`},
+	}
+	for _, tt := range tests {
+		set := &HashtagSet{Hashtags: []HashtagSpec{{Name: "#release", Settings: tt.settings}}}
+		changes := DiffHashtags(set, group, existing)
+		got := ""
+		if len(changes) > 1 {
+			t.Errorf("%s: DiffHashtags() = %v, want at most one change", tt.name, changes)
+			continue
+		}
+		if len(changes) == 1 {
+			got = changes[0].String()
+		}
+		if got != tt.want {
+			t.Errorf("%s: DiffHashtags() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package groupsclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	. "fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParsedMessage is an RFC 5322 message, such as PendingMsg.RawMessage, decoded for display and rule matching
+type ParsedMessage struct {
+	Header mail.Header
+	// Subject, From, To and Date are the decoded values of those headers
+	Subject string
+	From    string
+	To      string
+	Date    string
+	// TextBody and HTMLBody are the first text/plain and text/html parts, converted to UTF-8
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// Attachment describes a non-body part of a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Size        int
+}
+
+// wordDecoder decodes RFC 2047 encoded-words in headers using the same charsets as message bodies
+var wordDecoder = &mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		b, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		s, err := decodeCharset(charset, b)
+		return strings.NewReader(s), err
+	},
+}
+
+// Parse decodes msg.RawMessage
+func (msg PendingMsg) Parse() (*ParsedMessage, error) {
+	if msg.RawMessage == "" {
+		return nil, Errorf("PendingMsg.Parse: message %d has no raw_message", msg.ID)
+	}
+	return ParseRawMessage(msg.RawMessage)
+}
+
+// ParseRawMessage decodes the headers, body parts and attachments of the RFC 5322 message in raw
+func ParseRawMessage(raw string) (*ParsedMessage, error) {
+	m, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		return nil, Errorf("ParseRawMessage: %w", err)
+	}
+	p := &ParsedMessage{Header: m.Header}
+	p.Subject = p.HeaderValue("Subject")
+	p.From = p.HeaderValue("From")
+	p.To = p.HeaderValue("To")
+	p.Date = p.HeaderValue("Date")
+	err = p.addPart(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Header.Get("Content-Disposition"), m.Body)
+	return p, err
+}
+
+// HeaderValue returns the decoded value of the first header called name, or "" if there is none
+func (p *ParsedMessage) HeaderValue(name string) string {
+	v := p.Header.Get(name)
+	decoded, err := wordDecoder.DecodeHeader(v)
+	if err != nil {
+		return v
+	}
+	return decoded
+}
+
+// addPart decodes a single MIME part, recursing into multipart containers
+func (p *ParsedMessage) addPart(contentType string, encoding string, disposition string, body io.Reader) error {
+	if contentType == "" {
+		contentType = "text/plain; charset=us-ascii"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return Errorf("ParseRawMessage: %s: %w", mediaType, err)
+			}
+			err = p.addPart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"),
+				part.Header.Get("Content-Disposition"), part)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(transferDecoder(encoding, body))
+	if err != nil {
+		return Errorf("ParseRawMessage: decoding %s part: %w", mediaType, err)
+	}
+
+	dispType, dispParams, _ := mime.ParseMediaType(disposition)
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if filename != "" {
+		if decoded, err := wordDecoder.DecodeHeader(filename); err == nil {
+			filename = decoded
+		}
+	}
+	isAttachment := dispType == "attachment" || filename != ""
+
+	switch {
+	case mediaType == "text/plain" && !isAttachment && p.TextBody == "":
+		p.TextBody, _ = decodeCharset(params["charset"], data)
+	case mediaType == "text/html" && !isAttachment && p.HTMLBody == "":
+		p.HTMLBody, _ = decodeCharset(params["charset"], data)
+	case mediaType == "message/rfc822" && !isAttachment:
+		p.Attachments = append(p.Attachments, Attachment{Filename: "forwarded message", ContentType: mediaType, Size: len(data)})
+	default:
+		p.Attachments = append(p.Attachments, Attachment{Filename: filename, ContentType: mediaType, Size: len(data)})
+	}
+	return nil
+}
+
+// transferDecoder returns a reader that undoes the Content-Transfer-Encoding of body
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &base64Cleaner{r: body})
+	default:
+		return body
+	}
+}
+
+// base64Cleaner drops the line breaks and other whitespace that wrap base64 encoded bodies
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func (b *base64Cleaner) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	kept := 0
+	for _, c := range p[:n] {
+		if c != '\r' && c != '\n' && c != ' ' && c != '\t' {
+			p[kept] = c
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// windows1252 maps the bytes 0x80 to 0x9f, where windows-1252 differs from iso-8859-1, to their runes
+var windows1252 = [32]rune{
+	'€', '�', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '�', 'Ž', '�',
+	'�', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '�', 'ž', 'Ÿ',
+}
+
+// iso885915 maps the bytes where iso-8859-15 differs from iso-8859-1 to their runes
+var iso885915 = map[byte]rune{
+	0xa4: '€', 0xa6: 'Š', 0xa8: 'š', 0xb4: 'Ž', 0xb8: 'ž', 0xbc: 'Œ', 0xbd: 'œ', 0xbe: 'Ÿ',
+}
+
+// decodeCharset converts b from charset to a UTF-8 string. utf-8, us-ascii, iso-8859-1, iso-8859-15 and
+// windows-1252 are understood, anything else is returned as is along with an error.
+func decodeCharset(charset string, b []byte) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		if utf8.Valid(b) {
+			return string(b), nil
+		}
+		return strings.ToValidUTF8(string(b), "�"), nil
+	case "iso-8859-15", "latin9":
+		var sb strings.Builder
+		for _, c := range b {
+			if r, ok := iso885915[c]; ok {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteRune(rune(c))
+			}
+		}
+		return sb.String(), nil
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		var sb strings.Builder
+		for _, c := range b {
+			if c >= 0x80 && c <= 0x9f {
+				sb.WriteRune(windows1252[c-0x80])
+			} else {
+				sb.WriteRune(rune(c))
+			}
+		}
+		return sb.String(), nil
+	default:
+		return string(b), Errorf("unsupported charset %q", charset)
+	}
+}
+
+var (
+	htmlBreaks   = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/tr|/h[1-6])[^>]*>`)
+	htmlTags     = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlComments = regexp.MustCompile(`(?s)<!--.*?-->|<(?i:style|script)[^>]*>.*?</(?i:style|script)>`)
+	blankLines   = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText makes a rough plain text rendering of an HTML body for previews
+func htmlToText(html string) string {
+	text := htmlComments.ReplaceAllString(html, "")
+	text = htmlBreaks.ReplaceAllString(text, "\n")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'").Replace(text)
+	return strings.TrimSpace(blankLines.ReplaceAllString(text, "\n\n"))
+}
+
+// Text returns the plain text body, falling back to a rendering of the HTML body when there is no text part
+func (p *ParsedMessage) Text() string {
+	if strings.TrimSpace(p.TextBody) != "" {
+		return p.TextBody
+	}
+	return htmlToText(p.HTMLBody)
+}
+
+// Printable drops the control characters, other than newline and tab, from s so that untrusted message text cannot
+// move the cursor or send escape sequences to the terminal it is printed on
+func Printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Preview renders the headers, at most maxLines lines of the body and the list of attachments as readable text,
+// maxLines of 0 or less shows the whole body. Control characters are stripped from everything taken from the message.
+func (p *ParsedMessage) Preview(maxLines int) string {
+	var b bytes.Buffer
+	Fprintf(&b, "From:    %s\n", Printable(p.From))
+	Fprintf(&b, "To:      %s\n", Printable(p.To))
+	Fprintf(&b, "Date:    %s\n", Printable(p.Date))
+	Fprintf(&b, "Subject: %s\n\n", Printable(p.Subject))
+	lines := strings.Split(Printable(strings.ReplaceAll(p.Text(), "\r\n", "\n")), "\n")
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = append(lines[:maxLines], Sprintf("[... %d more lines]", len(lines)-maxLines))
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n")
+	if len(p.Attachments) > 0 {
+		Fprintf(&b, "\nAttachments:\n")
+		for _, a := range p.Attachments {
+			Fprintf(&b, "  %s (%s, %d bytes)\n", Printable(a.Filename), a.ContentType, a.Size)
+		}
+	}
+	return b.String()
+}
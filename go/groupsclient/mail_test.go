@@ -0,0 +1,138 @@
+package groupsclient
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeCharset(t *testing.T) {
+	tests := []struct {
+		charset string
+		in      []byte
+		want    string
+		wantErr bool
+	}{
+		{"", []byte("plain"), "plain", false},
+		{"UTF-8", []byte("caf\xc3\xa9"), "café", false},
+		{"us-ascii", []byte("bad \xff byte"), "bad � byte", false},
+		{"iso-8859-1", []byte("caf\xe9"), "café", false},
+		{"windows-1252", []byte("\x93quoted\x94 \x80"), "“quoted” €", false},
+		{"iso-8859-15", []byte("\xa4 \xbd"), "€ œ", false},
+		{"koi8-r", []byte("raw"), "raw", true},
+	}
+	for _, tt := range tests {
+		got, err := decodeCharset(tt.charset, tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decodeCharset(%q) error = %v, wantErr %t", tt.charset, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("decodeCharset(%q) = %q, want %q", tt.charset, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"<p>one</p><p>two</p>", "one\ntwo"},
+		{"line<br>next<BR/>last", "line\nnext\nlast"},
+		{"<style>p{color:red}</style><!-- note -->body", "body"},
+		{"<script>alert(1)</script>a &amp; b &lt;c&gt;", "a & b <c>"},
+		{"<div>a</div>\n\n\n\n<div>b</div>", "a\n\nb"},
+	}
+	for _, tt := range tests {
+		if got := htmlToText(tt.in); got != tt.want {
+			t.Errorf("htmlToText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRawMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		subject     string
+		text        string
+		attachments []string
+	}{
+		{
+			name:    "plain",
+			raw:     "Subject: hello\r\nFrom: a@example.com\r\n\r\nbody\r\n",
+			subject: "hello",
+			text:    "body\r\n",
+		},
+		{
+			name: "encoded subject and quoted-printable body",
+			raw: "Subject: =?iso-8859-1?q?caf=E9?=\r\nContent-Type: text/plain; charset=iso-8859-1\r\n" +
+				"Content-Transfer-Encoding: quoted-printable\r\n\r\ncaf=E9 =\r\nau lait\r\n",
+			subject: "café",
+			text:    "café au lait\r\n",
+		},
+		{
+			name: "html only",
+			raw: "Subject: html\r\nContent-Type: multipart/alternative; boundary=b\r\n\r\n" +
+				"--b\r\nContent-Type: text/html\r\n\r\n<p>hi</p><p>there</p>\r\n--b--\r\n",
+			subject: "html",
+			text:    "hi\nthere",
+		},
+		{
+			name: "base64 attachment",
+			raw: "Subject: files\r\nContent-Type: multipart/mixed; boundary=b\r\n\r\n" +
+				"--b\r\nContent-Type: text/plain\r\n\r\nsee attached\r\n" +
+				"--b\r\nContent-Type: application/pdf; name=report.pdf\r\nContent-Transfer-Encoding: base64\r\n\r\n" +
+				"aGVs\r\nbG8=\r\n--b--\r\n",
+			subject:     "files",
+			text:        "see attached",
+			attachments: []string{"report.pdf"},
+		},
+	}
+	for _, tt := range tests {
+		p, err := ParseRawMessage(tt.raw)
+		if err != nil {
+			t.Errorf("%s: ParseRawMessage() error = %v", tt.name, err)
+			continue
+		}
+		if p.Subject != tt.subject {
+			t.Errorf("%s: Subject = %q, want %q", tt.name, p.Subject, tt.subject)
+		}
+		if got := p.Text(); got != tt.text {
+			t.Errorf("%s: Text() = %q, want %q", tt.name, got, tt.text)
+		}
+		if len(p.Attachments) != len(tt.attachments) {
+			t.Errorf("%s: %d attachments, want %d", tt.name, len(p.Attachments), len(tt.attachments))
+			continue
+		}
+		for i, a := range p.Attachments {
+			if a.Filename != tt.attachments[i] {
+				t.Errorf("%s: attachment %d = %q, want %q", tt.name, i, a.Filename, tt.attachments[i])
+			}
+		}
+	}
+}
+
+func TestPreviewStripsControlCharacters(t *testing.T) {
+	p := &ParsedMessage{
+		Subject:     "hi\x1b[2J",
+		From:        "a@example.com\r",
+		TextBody:    "line one\x1b]0;title\x07\n\tline two\x08\n",
+		Attachments: []Attachment{{Filename: "evil\x1b[31m.pdf", ContentType: "application/pdf", Size: 5}},
+	}
+	got := p.Preview(0)
+	if strings.ContainsAny(got, "\x1b\x07\x08\r") {
+		t.Errorf("Preview() kept control characters: %q", got)
+	}
+	for _, want := range []string{"Subject: hi[2J\n", "line one]0;title\n\tline two\n", "evil[31m.pdf"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Preview() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestPreviewTruncates(t *testing.T) {
+	p := &ParsedMessage{TextBody: "1\n2\n3\n4"}
+	if got := p.Preview(2); !strings.Contains(got, "1\n2\n[... 2 more lines]\n") {
+		t.Errorf("Preview(2) = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package groupsclient
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Inline Markdown, applied after the text has been HTML escaped
+var (
+	mdCode   = regexp.MustCompile("`([^`]+)`")
+	mdBold   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdItalic = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	mdLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdURL    = regexp.MustCompile(`(^|[\s(])(https?://[^\s<)]*[^\s<).,;:!?])`)
+
+	mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdBullet  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdNumber  = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	mdRule    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+)
+
+// MarkdownToHTML renders the commonly used subset of Markdown, headings, paragraphs, lists, block quotes, fenced
+// code, rules, emphasis, code spans and links, as HTML suitable for a message body
+func MarkdownToHTML(md string) string {
+	var out strings.Builder
+	var para []string
+	list := ""
+	inCode := false
+
+	flushPara := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + mdInline(strings.Join(para, "\n")) + "</p>\n")
+			para = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			out.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			list = tag
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			if inCode {
+				out.WriteString("</code></pre>\n")
+			} else {
+				flushPara()
+				closeList()
+				out.WriteString("<pre><code>")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+		switch {
+		case strings.TrimSpace(line) == "":
+			flushPara()
+			closeList()
+		case mdRule.MatchString(line):
+			flushPara()
+			closeList()
+			out.WriteString("<hr>\n")
+		case mdHeading.MatchString(line):
+			flushPara()
+			closeList()
+			m := mdHeading.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			out.WriteString("<h" + level + ">" + mdInline(m[2]) + "</h" + level + ">\n")
+		case mdBullet.MatchString(line):
+			flushPara()
+			openList("ul")
+			out.WriteString("<li>" + mdInline(mdBullet.FindStringSubmatch(line)[1]) + "</li>\n")
+		case mdNumber.MatchString(line):
+			flushPara()
+			openList("ol")
+			out.WriteString("<li>" + mdInline(mdNumber.FindStringSubmatch(line)[1]) + "</li>\n")
+		case strings.HasPrefix(line, ">"):
+			flushPara()
+			closeList()
+			out.WriteString("<blockquote>" + mdInline(strings.TrimSpace(strings.TrimPrefix(line, ">"))) + "</blockquote>\n")
+		default:
+			closeList()
+			para = append(para, strings.TrimSpace(line))
+		}
+	}
+	if inCode {
+		out.WriteString("</code></pre>\n")
+	}
+	flushPara()
+	closeList()
+	return out.String()
+}
+
+// mdInline escapes s and renders its code spans, emphasis and links
+func mdInline(s string) string {
+	// code spans are set aside so that their contents are not rendered
+	var spans []string
+	s = mdCode.ReplaceAllStringFunc(html.EscapeString(s), func(m string) string {
+		spans = append(spans, "<code>"+mdCode.FindStringSubmatch(m)[1]+"</code>")
+		return "\x00" + strconv.Itoa(len(spans)-1) + "\x00"
+	})
+	s = mdLink.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = mdURL.ReplaceAllString(s, `$1<a href="$2">$2</a>`)
+	s = mdBold.ReplaceAllString(s, "<strong>$1</strong>")
+	s = mdItalic.ReplaceAllString(s, "<em>$1$2</em>")
+	for i, span := range spans {
+		s = strings.Replace(s, "\x00"+strconv.Itoa(i)+"\x00", span, 1)
+	}
+	return s
+}
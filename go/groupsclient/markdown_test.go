@@ -0,0 +1,33 @@
+package groupsclient
+
+import "testing"
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{"heading and emphasis", "# Title #\nplain *em* and **bold**",
+			"<h1>Title</h1>\n<p>plain <em>em</em> and <strong>bold</strong></p>\n"},
+		{"lists", "- a\n- b\n1. one\n2) two",
+			"<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n"},
+		{"fenced code is escaped, not rendered", "```\n<b>x</b> *no*\n```\nafter",
+			"<pre><code>&lt;b&gt;x&lt;/b&gt; *no*\n</code></pre>\n<p>after</p>\n"},
+		{"unclosed fence", "```\ncode", "<pre><code>code\n</code></pre>\n"},
+		{"block quote with code span", "> quoted `a*b*` here",
+			"<blockquote>quoted <code>a*b*</code> here</blockquote>\n"},
+		{"links and bare URLs", "see [docs](https://x.io/a) or https://y.io/b.",
+			"<p>see <a href=\"https://x.io/a\">docs</a> or <a href=\"https://y.io/b\">https://y.io/b</a>.</p>\n"},
+		{"rule", "---", "<hr>\n"},
+		{"paragraphs and HTML escaping", "a & <b>\nline two\n\nnext para",
+			"<p>a &amp; &lt;b&gt;\nline two</p>\n<p>next para</p>\n"},
+		{"underscores inside words", "snake_case_name and _em_", "<p>snake_case_name and <em>em</em></p>\n"},
+		{"CRLF line endings", "one\r\n\r\ntwo", "<p>one</p>\n<p>two</p>\n"},
+	}
+	for _, tt := range tests {
+		if got := MarkdownToHTML(tt.md); got != tt.want {
+			t.Errorf("%s: MarkdownToHTML(%q) = %q, want %q", tt.name, tt.md, got, tt.want)
+		}
+	}
+}
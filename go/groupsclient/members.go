@@ -0,0 +1,239 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	. "fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MemberAction names an administrative action that can be taken against a member of a group
+type MemberAction string
+
+const (
+	RemoveMemberAction MemberAction = "remove"
+	BanMemberAction    MemberAction = "ban"
+)
+
+// Permitted reports whether the Perms held by admin on its group allow action to be taken against other members
+func (action MemberAction) Permitted(admin MemberInfo) bool {
+	switch action {
+	case RemoveMemberAction:
+		return admin.Perms.RemoveMembers
+	case BanMemberAction:
+		return admin.Perms.BanMembers
+	default:
+		return false
+	}
+}
+
+// IsOwner reports whether the member is an owner of the group
+func (mi MemberInfo) IsOwner() bool {
+	return mi.ModStatus == ModStatusOwner
+}
+
+// IsModerator reports whether the member is a moderator of the group, owners are not counted as moderators
+func (mi MemberInfo) IsModerator() bool {
+	return mi.ModStatus == ModStatusModerator
+}
+
+// IsModerated reports whether the member's posts are held for moderation
+func (mi MemberInfo) IsModerated() bool {
+	return mi.PostStatus == PostStatusModerated || mi.PostStatus == PostStatusNewModerated
+}
+
+// IsBouncing reports whether email to the member is bouncing or has bounced
+func (mi MemberInfo) IsBouncing() bool {
+	return mi.Status == MemberStatusBouncing || mi.Status == MemberStatusBounced
+}
+
+// IsUnreachable reports whether email to the member is not getting through, because their account is unconfirmed or
+// bouncing or their subscription is bouncing
+func (mi MemberInfo) IsUnreachable() bool {
+	switch mi.UserStatus {
+	case UserStatusUnconfirmed, UserStatusBouncing, UserStatusBounced:
+		return true
+	}
+	return mi.IsBouncing() || mi.Status == MemberStatusNotConfirmed
+}
+
+// RemoveGroupMember removes memberId from groupId
+// https://groups.io/api#remove-member
+func (c *GroupsClient) RemoveGroupMember(groupId int, memberId int) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("member_info_id", strconv.Itoa(memberId))
+	if err := c.postForm("/api/v1/removemember", formData, nil); err != nil {
+		return Errorf("RemoveGroupMember: groupId %d, memberId %d: %w", groupId, memberId, err)
+	}
+	return nil
+}
+
+// BanGroupMember bans memberId from groupId, the member is removed and can not rejoin the group
+// https://groups.io/api#ban-member
+func (c *GroupsClient) BanGroupMember(groupId int, memberId int) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("member_info_id", strconv.Itoa(memberId))
+	if err := c.postForm("/api/v1/banmember", formData, nil); err != nil {
+		return Errorf("BanGroupMember: groupId %d, memberId %d: %w", groupId, memberId, err)
+	}
+	return nil
+}
+
+// ApplyMemberAction takes action against memberId on groupId
+func (c *GroupsClient) ApplyMemberAction(action MemberAction, groupId int, memberId int) error {
+	switch action {
+	case RemoveMemberAction:
+		return c.RemoveGroupMember(groupId, memberId)
+	case BanMemberAction:
+		return c.BanGroupMember(groupId, memberId)
+	default:
+		return Errorf("ApplyMemberAction: unknown action %q", action)
+	}
+}
+
+// MemberUpdate holds the member settings to change with UpdateGroupMember.
+// Fields left at their zero value are not sent and so are left unchanged on groups.io.
+type MemberUpdate struct {
+	ModStatus           ModStatus
+	PostStatus          PostStatus
+	EmailDelivery       EmailDelivery
+	MessageSelection    MessageSelection
+	PendingMsgNotify    NotifySetting
+	PendingSubNotify    NotifySetting
+	SubNotify           NotifySetting
+	StorageNotify       NotifySetting
+	SubGroupNotify      NotifySetting
+	MessageReportNotify NotifySetting
+	AccountNotify       NotifySetting
+	OwnerMsgNotify      NotifySetting
+	ChatNotify          NotifySetting
+	PhotoNotify         NotifySetting
+	FileNotify          NotifySetting
+	WikiNotify          NotifySetting
+	DatabaseNotify      NotifySetting
+	// ModPermissions is the list of permissions granted to a moderator, sent as a comma separated list
+	ModPermissions  []string
+	ExtraMemberData []ExtraMemberData
+}
+
+// notifyFields returns a pointer to each notify setting in u keyed by its updatemember parameter name
+func (u *MemberUpdate) notifyFields() map[string]*NotifySetting {
+	return map[string]*NotifySetting{
+		"pending_msg_notify":    &u.PendingMsgNotify,
+		"pending_sub_notify":    &u.PendingSubNotify,
+		"sub_notify":            &u.SubNotify,
+		"storage_notify":        &u.StorageNotify,
+		"sub_group_notify":      &u.SubGroupNotify,
+		"message_report_notify": &u.MessageReportNotify,
+		"account_notify":        &u.AccountNotify,
+		"owner_msg_notify":      &u.OwnerMsgNotify,
+		"chat_notify":           &u.ChatNotify,
+		"photo_notify":          &u.PhotoNotify,
+		"file_notify":           &u.FileNotify,
+		"wiki_notify":           &u.WikiNotify,
+		"database_notify":       &u.DatabaseNotify,
+	}
+}
+
+// IsEmpty reports whether u would not change anything
+func (u MemberUpdate) IsEmpty() bool {
+	for _, v := range u.notifyFields() {
+		if *v != "" {
+			return false
+		}
+	}
+	return u.ModStatus == "" && u.PostStatus == "" && u.EmailDelivery == "" && u.MessageSelection == "" &&
+		len(u.ModPermissions) == 0 && len(u.ExtraMemberData) == 0
+}
+
+// Validate checks every field set in u holds a known value before it is sent to groups.io
+func (u MemberUpdate) Validate() error {
+	if u.IsEmpty() {
+		return Errorf("MemberUpdate: no fields to update")
+	}
+	if u.ModStatus != "" && !u.ModStatus.Valid() {
+		return Errorf("MemberUpdate: unknown mod_status %q", u.ModStatus)
+	}
+	if u.PostStatus != "" && !u.PostStatus.Valid() {
+		return Errorf("MemberUpdate: unknown post_status %q", u.PostStatus)
+	}
+	if u.EmailDelivery != "" && !u.EmailDelivery.Valid() {
+		return Errorf("MemberUpdate: unknown email_delivery %q", u.EmailDelivery)
+	}
+	if u.MessageSelection != "" && !u.MessageSelection.Valid() {
+		return Errorf("MemberUpdate: unknown message_selection %q", u.MessageSelection)
+	}
+	for field, v := range u.notifyFields() {
+		if *v != "" && !v.Valid() {
+			return Errorf("MemberUpdate: unknown %s %q", field, *v)
+		}
+	}
+	for _, perm := range u.ModPermissions {
+		if perm == "" || strings.Contains(perm, ",") {
+			return Errorf("MemberUpdate: invalid mod_permissions entry %q", perm)
+		}
+	}
+	if len(u.ModPermissions) > 0 && u.ModStatus != "" && u.ModStatus != ModStatusModerator {
+		return Errorf("MemberUpdate: mod_permissions only apply to moderators, mod_status is %q", u.ModStatus)
+	}
+	return nil
+}
+
+// formValues returns the updatemember form parameters for every field set in u
+func (u MemberUpdate) formValues() (url.Values, error) {
+	formData := url.Values{}
+	setIf := func(field string, value string) {
+		if value != "" {
+			formData.Set(field, value)
+		}
+	}
+	setIf("mod_status", string(u.ModStatus))
+	setIf("post_status", string(u.PostStatus))
+	setIf("email_delivery", string(u.EmailDelivery))
+	setIf("message_selection", string(u.MessageSelection))
+	for field, v := range u.notifyFields() {
+		setIf(field, string(*v))
+	}
+	setIf("mod_permissions", strings.Join(u.ModPermissions, ","))
+	if len(u.ExtraMemberData) > 0 {
+		extra, err := json.Marshal(u.ExtraMemberData)
+		if err != nil {
+			return nil, err
+		}
+		formData.Set("extra_member_data", string(extra))
+	}
+	return formData, nil
+}
+
+// Permitted reports whether the Perms held by admin on its group allow u to be applied to other members
+func (u MemberUpdate) Permitted(admin MemberInfo) bool {
+	if (u.ModStatus != "" || len(u.ModPermissions) > 0) && !admin.Perms.MakeModerator {
+		return false
+	}
+	return admin.Perms.ManageMemberSubscriptionOptions || admin.Perms.ManageMembers
+}
+
+// SetNotify sets the notify setting named by its updatemember parameter, e.g. "pending_msg_notify", to value
+func (u *MemberUpdate) SetNotify(field string, value NotifySetting) error {
+	target, ok := u.notifyFields()[field]
+	if !ok {
+		return Errorf("MemberUpdate: unknown notify setting %q", field)
+	}
+	*target = value
+	return nil
+}
+
+// AddGroupMembers adds emails to groupId directly, without sending them an invitation to confirm
+// https://groups.io/api#direct-add
+func (c *GroupsClient) AddGroupMembers(groupId int, emails []string) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("emails", strings.Join(emails, "\n"))
+	if err := c.postForm("/api/v1/directadd", formData, nil); err != nil {
+		return Errorf("AddGroupMembers: groupId %d: %w", groupId, err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package groupsclient
+
+import (
+	. "fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrgSettings maps updateorg parameter names to their values
+type OrgSettings map[string]string
+
+// Settings returns the writable settings of o keyed by their updateorg parameter names
+func (o Org) Settings() OrgSettings {
+	return OrgSettings{
+		"title":             o.Title,
+		"default_timezone":  o.DefaultTimezone,
+		"disable_signup":    strconv.FormatBool(o.DisableSignup),
+		"disable_plus_one":  strconv.FormatBool(o.DisablePlusOne),
+		"ga_code":           o.GaCode,
+		"login_page_text":   o.LoginPageText,
+		"no_account_text":   o.NoAccountText,
+		"sso_provider":      o.SsoProvider,
+		"sso_client_id":     o.SsoClientID,
+		"sso_client_secret": o.SsoClientSecret,
+		"sso_domain":        o.SsoDomain,
+	}
+}
+
+// Validate checks every setting in s is a known updateorg parameter with a valid value, and rewrites boolean settings
+// given in any form strconv.ParseBool accepts, such as "1" or "F", as "true" or "false"
+func (s OrgSettings) Validate() error {
+	known := Org{}.Settings()
+	for name, value := range s {
+		if _, ok := known[name]; !ok {
+			return Errorf("OrgSettings: unknown setting %q", name)
+		}
+		switch name {
+		case "title":
+			if strings.TrimSpace(value) == "" {
+				return Errorf("OrgSettings: title can not be empty")
+			}
+		case "default_timezone":
+			if _, err := time.LoadLocation(value); err != nil || value == "" || value == "Local" {
+				return Errorf("OrgSettings: %q is not an IANA timezone name such as America/Los_Angeles", value)
+			}
+		case "disable_signup", "disable_plus_one":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return Errorf("OrgSettings: %s must be true or false, not %q", name, value)
+			}
+			s[name] = strconv.FormatBool(b)
+		}
+	}
+	return nil
+}
+
+// Redacted returns a copy of s with the values of settings backed by Org fields tagged sensitive:"true" redacted,
+// unless SetShowSecrets(true) has been called
+func (s OrgSettings) Redacted() OrgSettings {
+	redacted := make(OrgSettings, len(s))
+	for name, value := range s {
+		redacted[name] = value
+	}
+	if showSecrets.Load() {
+		return redacted
+	}
+	rt := reflect.TypeOf(Org{})
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Tag.Get("sensitive") == "true" && redacted[name] != "" {
+			redacted[name] = redactedValue
+		}
+	}
+	return redacted
+}
+
+// UpdateOrg changes the settings of the Org the client is authenticated against to those in settings, settings not in
+// it are left unchanged
+// https://groups.io/api#update-org
+func (c *GroupsClient) UpdateOrg(settings OrgSettings) (*Org, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	formData := GroupSettings(settings).formValues()
+	var org Org
+	if err := c.postForm("/api/v1/updateorg", formData, &org); err != nil {
+		return nil, Errorf("UpdateOrg: %w", err)
+	}
+	return &org, nil
+}
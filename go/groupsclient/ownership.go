@@ -0,0 +1,112 @@
+package groupsclient
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OwnershipFlag is a problem with who owns a group
+type OwnershipFlag string
+
+const (
+	FlagNoOwners          OwnershipFlag = "no-owners"
+	FlagSingleOwner       OwnershipFlag = "single-owner"
+	FlagNoReachableOwners OwnershipFlag = "no-reachable-owners"
+	// FlagError marks a group whose members could not be fetched, Error says why
+	FlagError OwnershipFlag = "error"
+)
+
+// GroupOwnership lists the owners and moderators of a group and any problems with them
+type GroupOwnership struct {
+	Group      string          `json:"group"`
+	GroupID    int             `json:"group_id"`
+	SubsCount  int             `json:"subs_count"`
+	Owners     []RoleHolder    `json:"owners"`
+	Moderators []RoleHolder    `json:"moderators"`
+	Flags      []OwnershipFlag `json:"flags,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// RoleHolder is an owner or moderator of a group. MembershipUpdated is when their membership of the group last
+// changed, it says nothing about when they were last active.
+type RoleHolder struct {
+	Name              string `json:"name"`
+	Email             string `json:"email"`
+	UserStatus        string `json:"user_status"`
+	Status            string `json:"status"`
+	MembershipUpdated string `json:"membership_updated"`
+	Unreachable       bool   `json:"unreachable,omitempty"`
+}
+
+func roleHolderOf(member MemberInfo) RoleHolder {
+	return RoleHolder{
+		Name:              member.FullName,
+		Email:             member.Email,
+		UserStatus:        member.UserStatus.String(),
+		Status:            member.Status.String(),
+		MembershipUpdated: member.Updated,
+		Unreachable:       member.IsUnreachable(),
+	}
+}
+
+// AuditOwnership fetches the owners and moderators of every subgroup of the Org whose name matches groupRe, or of
+// every subgroup when groupRe is nil, and flags groups with no owners, a single owner, or no reachable owners. A group
+// whose members cannot be fetched is included with FlagError rather than ending the audit.
+func (c *GroupsClient) AuditOwnership(groupRe *regexp.Regexp) ([]GroupOwnership, error) {
+	groups, _, err := c.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	audit := make([]GroupOwnership, 0, len(groups))
+	for _, group := range groups {
+		if groupRe != nil && !groupRe.MatchString(group.Name) {
+			continue
+		}
+		members, _, err := c.GetGroupMembers(group.ID)
+		if err != nil {
+			log.Printf("WARN AuditOwnership: group %s: %v", group.Name, err)
+			audit = append(audit, GroupOwnership{Group: group.Name, GroupID: group.ID, SubsCount: group.SubsCount,
+				Owners: make([]RoleHolder, 0), Moderators: make([]RoleHolder, 0), Flags: []OwnershipFlag{FlagError},
+				Error: err.Error()})
+			continue
+		}
+		audit = append(audit, NewGroupOwnership(group, members))
+	}
+	sort.Slice(audit, func(i, j int) bool { return strings.ToLower(audit[i].Group) < strings.ToLower(audit[j].Group) })
+	return audit, nil
+}
+
+// NewGroupOwnership picks the owners and moderators of group out of members and flags any problems with them
+func NewGroupOwnership(group Group, members []MemberInfo) GroupOwnership {
+	o := GroupOwnership{
+		Group:      group.Name,
+		GroupID:    group.ID,
+		SubsCount:  group.SubsCount,
+		Owners:     make([]RoleHolder, 0),
+		Moderators: make([]RoleHolder, 0),
+	}
+	reachableOwners := 0
+	for _, member := range members {
+		switch {
+		case member.IsOwner():
+			o.Owners = append(o.Owners, roleHolderOf(member))
+			if !member.IsUnreachable() {
+				reachableOwners++
+			}
+		case member.IsModerator():
+			o.Moderators = append(o.Moderators, roleHolderOf(member))
+		}
+	}
+	switch len(o.Owners) {
+	case 0:
+		o.Flags = append(o.Flags, FlagNoOwners)
+	case 1:
+		o.Flags = append(o.Flags, FlagSingleOwner)
+	}
+	if len(o.Owners) > 0 && reachableOwners == 0 {
+		o.Flags = append(o.Flags, FlagNoReachableOwners)
+	}
+	return o
+}
@@ -0,0 +1,183 @@
+package groupsclient
+
+import (
+	. "fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PendingMsgAction is a moderation action that can be taken on a PendingMsg
+type PendingMsgAction string
+
+const (
+	ApprovePendingMsgAction PendingMsgAction = "approve"
+	RejectPendingMsgAction  PendingMsgAction = "reject"
+	DeletePendingMsgAction  PendingMsgAction = "delete"
+	ClaimPendingMsgAction   PendingMsgAction = "claim"
+	// TrustPendingMsgAction approves the message and stops moderating its sender, see ApproveAndTrustPendingMsg
+	TrustPendingMsgAction PendingMsgAction = "approve-and-trust"
+)
+
+// managePendingMsg takes action on the pending message msg, formData holds any extra parameters for the action
+// https://groups.io/api#manage-pending-message
+func (c *GroupsClient) managePendingMsg(msg PendingMsg, action PendingMsgAction, formData url.Values) error {
+	if formData == nil {
+		formData = url.Values{}
+	}
+	formData.Set("group_id", strconv.Itoa(msg.GroupID))
+	formData.Set("pending_msg_id", strconv.Itoa(msg.ID))
+	formData.Set("action", string(action))
+	if err := c.postForm("/api/v1/managependingmessage", formData, nil); err != nil {
+		return Errorf("managePendingMsg: %s of message %d on groupId %d: %w", action, msg.ID, msg.GroupID, err)
+	}
+	return nil
+}
+
+// ApprovePendingMsg releases msg to the group
+func (c *GroupsClient) ApprovePendingMsg(msg PendingMsg) error {
+	return c.managePendingMsg(msg, ApprovePendingMsgAction, nil)
+}
+
+// RejectPendingMsg rejects msg, when reason is not empty it is sent to the sender of the message
+func (c *GroupsClient) RejectPendingMsg(msg PendingMsg, reason string) error {
+	formData := url.Values{}
+	if reason != "" {
+		formData.Set("notify_sender", "true")
+		formData.Set("reason", reason)
+	}
+	return c.managePendingMsg(msg, RejectPendingMsgAction, formData)
+}
+
+// DeletePendingMsg silently discards msg, the sender is not notified
+func (c *GroupsClient) DeletePendingMsg(msg PendingMsg) error {
+	return c.managePendingMsg(msg, DeletePendingMsgAction, nil)
+}
+
+// ClaimPendingMsg marks msg as being handled by the authenticated user so other moderators leave it alone
+func (c *GroupsClient) ClaimPendingMsg(msg PendingMsg) error {
+	return c.managePendingMsg(msg, ClaimPendingMsgAction, nil)
+}
+
+// GetPendingMsgList method to get the pending msgs of every group in which the authenticated user can manage pending
+// messages and whose name matches groupRe, or every such group when groupRe is nil. Each PendingMsg has the GroupName
+// of the group it is held in set. A group whose pending messages cannot be fetched is logged and skipped so that one
+// failure does not hide the messages held in every other group.
+func (c *GroupsClient) GetPendingMsgList(groupRe *regexp.Regexp) ([]PendingMsg, int, error) {
+	subs, _, err := c.GetMemberInfoList()
+	if err != nil {
+		return nil, 0, err
+	}
+	allPendingMsgs := make([]PendingMsg, 0)
+	for _, sub := range subs {
+		if !sub.Perms.ManagePendingMessages || (groupRe != nil && !groupRe.MatchString(sub.GroupName)) {
+			continue
+		}
+		groupPendingMsgs, _, err := c.GetGroupPendingMsgList(sub.GroupID)
+		if err != nil {
+			log.Printf("WARN GetPendingMsgList: skipping group %s: %v", sub.GroupName, err)
+			continue
+		}
+		for i := range groupPendingMsgs {
+			groupPendingMsgs[i].GroupName = sub.GroupName
+		}
+		allPendingMsgs = append(allPendingMsgs, groupPendingMsgs...)
+	}
+	return allPendingMsgs, len(allPendingMsgs), nil
+}
+
+// CreatedTime returns the time msg was held for moderation
+func (msg PendingMsg) CreatedTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, msg.Created)
+}
+
+// PendingMsgFilter selects pending messages, zero valued fields match every message
+type PendingMsgFilter struct {
+	// Group matches the name of the group the message is held in
+	Group *regexp.Regexp
+	// MinAge and MaxAge bound how long ago the message was held
+	MinAge time.Duration
+	MaxAge time.Duration
+	// Sender is matched, case insensitively, as a substring of the sender's email and name
+	Sender string
+}
+
+// Match reports whether msg is selected by f as of now
+func (f PendingMsgFilter) Match(msg PendingMsg, now time.Time) bool {
+	if f.Group != nil && !f.Group.MatchString(msg.GroupName) {
+		return false
+	}
+	if f.Sender != "" {
+		sender := strings.ToLower(f.Sender)
+		if !strings.Contains(strings.ToLower(msg.SenderEmail), sender) && !strings.Contains(strings.ToLower(msg.SenderName), sender) {
+			return false
+		}
+	}
+	if f.MinAge > 0 || f.MaxAge > 0 {
+		created, err := msg.CreatedTime()
+		if err != nil {
+			return false
+		}
+		age := now.Sub(created)
+		if f.MinAge > 0 && age < f.MinAge {
+			return false
+		}
+		if f.MaxAge > 0 && age > f.MaxAge {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterPendingMsgs returns the messages in msgs selected by f
+func FilterPendingMsgs(msgs []PendingMsg, f PendingMsgFilter) []PendingMsg {
+	now := time.Now()
+	filtered := make([]PendingMsg, 0, len(msgs))
+	for _, msg := range msgs {
+		if f.Match(msg, now) {
+			filtered = append(filtered, msg)
+		}
+	}
+	return filtered
+}
+
+// ApproveAndTrustPendingMsg approves msg and sets its sender's post status to unmoderated in the group it was held in,
+// so their future posts are not held. heldIn is the authenticated user's subscription to that group, nothing is done
+// unless its Perms allow the sender to be unmoderated. When alsoTrustIn is not empty the sender's post status is also
+// set to unmoderated in each of those groups they are a member of. Returns the number of memberships updated.
+func (c *GroupsClient) ApproveAndTrustPendingMsg(msg PendingMsg, heldIn MemberInfo, alsoTrustIn []MemberInfo) (int, error) {
+	trust := MemberUpdate{PostStatus: PostStatusUnmoderated}
+	if heldIn.GroupID != msg.GroupID || !trust.Permitted(heldIn) {
+		return 0, Errorf("ApproveAndTrustPendingMsg: no permission to unmoderate the sender of message %d in %s", msg.ID, msg.GroupName)
+	}
+	if err := c.ApprovePendingMsg(msg); err != nil {
+		return 0, err
+	}
+	memberId, err := c.GetMemberId(msg.GroupID, msg.UserID)
+	if err != nil {
+		return 0, Errorf("ApproveAndTrustPendingMsg: message %d approved but sender not found: %w", msg.ID, err)
+	}
+	if _, err := c.UpdateGroupMember(msg.GroupID, memberId, trust); err != nil {
+		return 0, Errorf("ApproveAndTrustPendingMsg: message %d approved but sender not trusted: %w", msg.ID, err)
+	}
+	updated := 1
+	for _, group := range alsoTrustIn {
+		if group.GroupID == msg.GroupID || !trust.Permitted(group) {
+			continue
+		}
+		memberId, err := c.GetMemberId(group.GroupID, msg.UserID)
+		if err != nil {
+			// the sender need not be a member of every group
+			continue
+		}
+		if _, err := c.UpdateGroupMember(group.GroupID, memberId, trust); err != nil {
+			log.Printf("WARN ApproveAndTrustPendingMsg: sender of message %d not trusted in %s: %v", msg.ID, group.GroupName, err)
+			continue
+		}
+		updated++
+	}
+	return updated, nil
+}
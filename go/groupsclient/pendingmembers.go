@@ -0,0 +1,103 @@
+package groupsclient
+
+import (
+	. "fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GetPendingMembers returns the members awaiting approval to join each group in which the authenticated user can
+// manage pending members and whose name matches groupRe, or every such group when groupRe is nil. groups.io reports
+// the group name on each MemberInfo. A group whose members cannot be fetched is logged and skipped.
+func (c *GroupsClient) GetPendingMembers(groupRe *regexp.Regexp) ([]MemberInfo, error) {
+	subs, _, err := c.GetMemberInfoList()
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]MemberInfo, 0)
+	for _, sub := range subs {
+		if !sub.Perms.ManagePendingMembers || (groupRe != nil && !groupRe.MatchString(sub.GroupName)) {
+			continue
+		}
+		members, _, err := c.GetGroupMembers(sub.GroupID)
+		if err != nil {
+			log.Printf("WARN GetPendingMembers: skipping group %s: %v", sub.GroupName, err)
+			continue
+		}
+		for _, member := range members {
+			if member.Status != MemberStatusPendingApproval {
+				continue
+			}
+			if member.GroupName == "" {
+				member.GroupName = sub.GroupName
+			}
+			pending = append(pending, member)
+		}
+	}
+	return pending, nil
+}
+
+// ApprovePendingMember lets the pending member join the group they applied to
+// https://groups.io/api#approve-member
+func (c *GroupsClient) ApprovePendingMember(member MemberInfo) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(member.GroupID))
+	formData.Set("member_info_id", strconv.Itoa(member.ID))
+	if err := c.postForm("/api/v1/approvemember", formData, nil); err != nil {
+		return Errorf("ApprovePendingMember: %s on %s: %w", member.Email, member.GroupName, err)
+	}
+	return nil
+}
+
+// RejectPendingMember turns down the pending member's request to join, when reason is not empty it is sent to them
+// https://groups.io/api#reject-member
+func (c *GroupsClient) RejectPendingMember(member MemberInfo, reason string) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(member.GroupID))
+	formData.Set("member_info_id", strconv.Itoa(member.ID))
+	if reason != "" {
+		formData.Set("notify_member", "true")
+		formData.Set("reason", reason)
+	}
+	if err := c.postForm("/api/v1/rejectmember", formData, nil); err != nil {
+		return Errorf("RejectPendingMember: %s on %s: %w", member.Email, member.GroupName, err)
+	}
+	return nil
+}
+
+// EmailDomainIn reports whether the domain of the member's email address is one of domains, ignoring case
+func (mi MemberInfo) EmailDomainIn(domains []string) bool {
+	_, domain, found := strings.Cut(mi.Email, "@")
+	return found && containsFold(domains, domain)
+}
+
+// Value returns the member's answer held in d as text
+func (d ExtraMemberData) Value() string {
+	switch {
+	case d.Text != "":
+		return d.Text
+	case d.URL != "":
+		return strings.TrimSpace(d.Title + " " + d.URL)
+	case d.StreetAddress1 != "" || d.City != "":
+		parts := make([]string, 0, 6)
+		for _, part := range []string{d.StreetAddress1, d.StreetAddress2, d.City, d.State, d.Zip, d.Country} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case !d.Date.IsZero():
+		return d.Date.Format("2006-01-02")
+	case !d.Time.IsZero():
+		return d.Time.Format("15:04")
+	case d.ImageName != "":
+		return d.ImageName
+	case d.ColType == "checkbox":
+		return strconv.FormatBool(d.Checked)
+	default:
+		return d.Desc
+	}
+}
@@ -0,0 +1,89 @@
+package groupsclient
+
+import (
+	. "fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Post is a new message to send to one or more groups. Body is Markdown when Markdown is set and plain text otherwise.
+type Post struct {
+	Subject  string
+	Body     string
+	Markdown bool
+	// Hashtags are appended to the subject, which is how groups.io tags a new topic
+	Hashtags []string
+	// Attachments are paths of local files sent with the message
+	Attachments []string
+}
+
+// Validate checks p has a subject and body and that each of its attachments is a readable file
+func (p Post) Validate() error {
+	if strings.TrimSpace(p.Subject) == "" {
+		return Errorf("Post: no subject")
+	}
+	if strings.TrimSpace(p.Body) == "" {
+		return Errorf("Post: %q has no body", p.Subject)
+	}
+	for _, tag := range p.Hashtags {
+		if strings.ContainsAny(strings.TrimPrefix(tag, "#"), " \t#") || strings.TrimPrefix(tag, "#") == "" {
+			return Errorf("Post: %q is not a hashtag", tag)
+		}
+	}
+	for _, path := range p.Attachments {
+		info, err := os.Stat(path)
+		if err != nil {
+			return Errorf("Post: attachment: %w", err)
+		}
+		if info.IsDir() {
+			return Errorf("Post: attachment %s is a directory", path)
+		}
+	}
+	return nil
+}
+
+// RenderedSubject is the subject of p followed by its hashtags
+func (p Post) RenderedSubject() string {
+	subject := strings.TrimSpace(p.Subject)
+	for _, tag := range p.Hashtags {
+		subject += " #" + strings.TrimPrefix(tag, "#")
+	}
+	return subject
+}
+
+// RenderedBody is the body of p as it will be sent, HTML rendered from Markdown or the plain text as is, and whether
+// it is plain text
+func (p Post) RenderedBody() (string, bool) {
+	if p.Markdown {
+		return MarkdownToHTML(p.Body), false
+	}
+	return p.Body, true
+}
+
+// PostMessage sends post to groupId as a new topic, from the authenticated user
+// https://groups.io/api#post-message
+func (c *GroupsClient) PostMessage(groupId int, post Post) (*Message, error) {
+	if err := post.Validate(); err != nil {
+		return nil, err
+	}
+	body, plain := post.RenderedBody()
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("subject", post.RenderedSubject())
+	formData.Set("body", body)
+	formData.Set("is_plain_text", strconv.FormatBool(plain))
+
+	var msg Message
+	var err error
+	if len(post.Attachments) > 0 {
+		err = c.postMultipart("/api/v1/postmessage", formData, "attachment", post.Attachments, &msg)
+	} else {
+		err = c.postForm("/api/v1/postmessage", formData, &msg)
+	}
+	if err != nil {
+		return nil, Errorf("PostMessage: groupId %d %q: %w", groupId, post.Subject, err)
+	}
+	return &msg, nil
+}
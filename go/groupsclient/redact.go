@@ -0,0 +1,70 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	. "fmt"
+	"log/slog"
+	"reflect"
+	"sync/atomic"
+)
+
+// redactedValue replaces the value of a field tagged sensitive:"true" when it is printed, logged or exported
+const redactedValue = "[REDACTED]"
+
+var showSecrets atomic.Bool
+
+// SetShowSecrets controls whether fields tagged sensitive:"true" are shown as is, it is off by default
+func SetShowSecrets(show bool) {
+	showSecrets.Store(show)
+}
+
+// Redact returns a copy of v, a struct, with each non-empty string field tagged sensitive:"true" replaced by
+// [REDACTED], unless SetShowSecrets(true) has been called
+func Redact[T any](v T) T {
+	if showSecrets.Load() {
+		return v
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.Struct {
+		return v
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Tag.Get("sensitive") != "true" || field.Type.Kind() != reflect.String || !field.IsExported() {
+			continue
+		}
+		if rv.Field(i).String() != "" {
+			rv.Field(i).SetString(redactedValue)
+		}
+	}
+	return v
+}
+
+// formatRedacted prints v, which should already be redacted, honouring the verb and flags in f
+func formatRedacted(f State, verb rune, v interface{}) {
+	Fprintf(f, FormatString(f, verb), v)
+}
+
+// plainOrg, plainUser, plainClient and plainWebhook have the fields but not the methods of the types they convert, so
+// they can be printed without recursing back into Format
+type (
+	plainOrg     Org
+	plainUser    User
+	plainClient  GroupsClient
+	plainWebhook Webhook
+)
+
+func (o Org) Format(f State, verb rune)    { formatRedacted(f, verb, plainOrg(Redact(o))) }
+func (o Org) LogValue() slog.Value         { return slog.AnyValue(plainOrg(Redact(o))) }
+func (o Org) MarshalJSON() ([]byte, error) { return json.Marshal(plainOrg(Redact(o))) }
+
+func (u User) Format(f State, verb rune)    { formatRedacted(f, verb, plainUser(Redact(u))) }
+func (u User) LogValue() slog.Value         { return slog.AnyValue(plainUser(Redact(u))) }
+func (u User) MarshalJSON() ([]byte, error) { return json.Marshal(plainUser(Redact(u))) }
+
+func (c GroupsClient) Format(f State, verb rune) { formatRedacted(f, verb, plainClient(Redact(c))) }
+func (c GroupsClient) LogValue() slog.Value      { return slog.AnyValue(plainClient(Redact(c))) }
+
+func (hook Webhook) Format(f State, verb rune) { formatRedacted(f, verb, plainWebhook(Redact(hook))) }
+func (hook Webhook) LogValue() slog.Value      { return slog.AnyValue(plainWebhook(Redact(hook))) }
@@ -0,0 +1,423 @@
+package groupsclient
+
+import (
+	"encoding/json"
+	. "fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the declarative description of the Org's subgroups read by LoadSpec
+type Spec struct {
+	Groups []GroupSpec `yaml:"groups" json:"groups"`
+}
+
+// GroupSpec declares the settings and roles of one subgroup.
+// Only the settings listed are reconciled, restrictions such as restricted and approve_members are settings too.
+// Owners and moderators are always reconciled, Members only when it is present, in which case it need not repeat
+// the owners and moderators. Members missing from Members are only removed when Prune is set.
+type GroupSpec struct {
+	Name       string        `yaml:"name" json:"name"`
+	Settings   GroupSettings `yaml:"settings,omitempty" json:"settings,omitempty"`
+	Owners     []string      `yaml:"owners,omitempty" json:"owners,omitempty"`
+	Moderators []string      `yaml:"moderators,omitempty" json:"moderators,omitempty"`
+	Members    *[]string     `yaml:"members,omitempty" json:"members,omitempty"`
+	Prune      bool          `yaml:"prune,omitempty" json:"prune,omitempty"`
+}
+
+// Role names used in a Spec and in the From and To of role changes
+const (
+	RoleOwner     = "owner"
+	RoleModerator = "moderator"
+	RoleMember    = "member"
+)
+
+// roleOf returns the Spec role name of a member's ModStatus
+func roleOf(status ModStatus) string {
+	switch status {
+	case ModStatusOwner:
+		return RoleOwner
+	case ModStatusModerator:
+		return RoleModerator
+	default:
+		return RoleMember
+	}
+}
+
+// roleRank orders role names from member, the lowest, to owner
+var roleRank = map[string]int{RoleMember: 0, RoleModerator: 1, RoleOwner: 2}
+
+// modStatusOf returns the ModStatus of a Spec role name
+func modStatusOf(role string) ModStatus {
+	switch role {
+	case RoleOwner:
+		return ModStatusOwner
+	case RoleModerator:
+		return ModStatusModerator
+	default:
+		return ModStatusNone
+	}
+}
+
+// LoadSpec reads and validates the Spec at path, which is JSON when it has a .json extension and YAML otherwise
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &spec)
+	} else {
+		err = yaml.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return nil, Errorf("LoadSpec: %s: %w", path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, Errorf("LoadSpec: %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Validate checks group names are unique, settings are known and nobody is declared with two roles in a group
+func (s *Spec) Validate() error {
+	names := make(map[string]bool, len(s.Groups))
+	for _, gs := range s.Groups {
+		if gs.Name == "" {
+			return Errorf("group with no name")
+		}
+		if names[strings.ToLower(gs.Name)] {
+			return Errorf("group %s declared more than once", gs.Name)
+		}
+		names[strings.ToLower(gs.Name)] = true
+		if err := gs.Settings.Validate(); err != nil {
+			return Errorf("group %s: %w", gs.Name, err)
+		}
+		seen := make(map[string]string)
+		for role, emails := range map[string][]string{RoleOwner: gs.Owners, RoleModerator: gs.Moderators} {
+			for _, email := range emails {
+				email = strings.ToLower(strings.TrimSpace(email))
+				if other, dup := seen[email]; dup {
+					return Errorf("group %s: %s declared as both %s and %s", gs.Name, email, other, role)
+				}
+				seen[email] = role
+			}
+		}
+	}
+	return nil
+}
+
+// roles returns the declared role of each member of gs keyed by lower cased email
+func (gs GroupSpec) roles() map[string]string {
+	roles := make(map[string]string)
+	if gs.Members != nil {
+		for _, email := range *gs.Members {
+			roles[strings.ToLower(strings.TrimSpace(email))] = RoleMember
+		}
+	}
+	for _, email := range gs.Moderators {
+		roles[strings.ToLower(strings.TrimSpace(email))] = RoleModerator
+	}
+	for _, email := range gs.Owners {
+		roles[strings.ToLower(strings.TrimSpace(email))] = RoleOwner
+	}
+	return roles
+}
+
+// ChangeKind is the kind of difference between a Spec and the live state of the Org
+type ChangeKind string
+
+const (
+	ChangeCreateGroup   ChangeKind = "create-group"
+	ChangeUpdateSetting ChangeKind = "update-setting"
+	ChangeAddMember     ChangeKind = "add-member"
+	ChangeSetRole       ChangeKind = "set-role"
+	ChangeRemoveMember  ChangeKind = "remove-member"
+)
+
+// Change is one difference between a Spec and live state, and the action that removes it
+type Change struct {
+	Kind    ChangeKind `json:"kind"`
+	Group   string     `json:"group"`
+	GroupID int        `json:"group_id,omitempty"`
+	// Subject is the setting name or the member email the change is about
+	Subject string `json:"subject,omitempty"`
+	// From and To are the live and declared setting values or roles
+	From     string        `json:"from,omitempty"`
+	To       string        `json:"to,omitempty"`
+	MemberID int           `json:"member_id,omitempty"`
+	Settings GroupSettings `json:"settings,omitempty"`
+}
+
+// String describes c in a single line for plans and reports
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeCreateGroup:
+		return Sprintf("+ create group %s", c.Group)
+	case ChangeUpdateSetting:
+		return Sprintf("~ %s: setting %s %q -> %q", c.Group, c.Subject, c.From, c.To)
+	case ChangeAddMember:
+		return Sprintf("+ %s: add %s as %s", c.Group, c.Subject, c.To)
+	case ChangeSetRole:
+		return Sprintf("~ %s: %s %s -> %s", c.Group, c.Subject, c.From, c.To)
+	case ChangeRemoveMember:
+		return Sprintf("- %s: remove %s (%s)", c.Group, c.Subject, c.From)
+	default:
+		return Sprintf("? %s: %s %s", c.Group, c.Kind, c.Subject)
+	}
+}
+
+// Plan is the ordered list of changes that would bring the Org in line with a Spec
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasChanges reports whether live state differs from the Spec the plan was made from
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// LiveGroup is the state of a subgroup fetched from groups.io
+type LiveGroup struct {
+	Group   Group
+	Members []MemberInfo
+}
+
+// GetLiveGroups fetches every subgroup of the Org and, for those named in withMembers, their members, keyed by the
+// lower cased group name
+func (c *GroupsClient) GetLiveGroups(withMembers map[string]bool) (map[string]*LiveGroup, error) {
+	groups, _, err := c.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]*LiveGroup, len(groups))
+	for _, group := range groups {
+		lg := &LiveGroup{Group: group}
+		if withMembers[strings.ToLower(group.Name)] {
+			lg.Members, _, err = c.GetGroupMembers(group.ID)
+			if err != nil {
+				return nil, Errorf("GetLiveGroups: %s: %w", group.Name, err)
+			}
+		}
+		live[strings.ToLower(group.Name)] = lg
+	}
+	return live, nil
+}
+
+// PlanSync compares spec with the live state of the Org and returns the changes needed to make them match. It refuses,
+// with an error, a plan that would lock actor or every owner out of a group, see Plan.CheckOwnership.
+func (c *GroupsClient) PlanSync(spec *Spec, actor string) (*Plan, error) {
+	declared := make(map[string]bool, len(spec.Groups))
+	for _, gs := range spec.Groups {
+		declared[strings.ToLower(gs.Name)] = true
+	}
+	live, err := c.GetLiveGroups(declared)
+	if err != nil {
+		return nil, err
+	}
+	plan := DiffSpec(spec, live)
+	if err := plan.CheckOwnership(actor, live); err != nil {
+		return nil, Errorf("PlanSync: %w", err)
+	}
+	return plan, nil
+}
+
+// CheckOwnership returns an error when plan demotes or removes actor in any group, or leaves a group in live with no
+// owners
+func (p *Plan) CheckOwnership(actor string, live map[string]*LiveGroup) error {
+	actor = strings.ToLower(strings.TrimSpace(actor))
+	owners := make(map[string]int)
+	for name, lg := range live {
+		for _, member := range lg.Members {
+			if member.ModStatus == ModStatusOwner && !member.isUnconfirmed() {
+				owners[name]++
+			}
+		}
+	}
+	// groups the plan takes an owner away from
+	demoted := make(map[string]string)
+	for _, change := range p.Changes {
+		group := strings.ToLower(change.Group)
+		switch change.Kind {
+		case ChangeSetRole:
+			if change.Subject == actor && roleRank[change.To] < roleRank[change.From] {
+				return Errorf("%s: refusing to demote %s, the user running sync, from %s to %s", change.Group, actor, change.From, change.To)
+			}
+			if change.From == RoleOwner {
+				owners[group]--
+				demoted[group] = change.Group
+			} else if change.To == RoleOwner {
+				owners[group]++
+			}
+		case ChangeRemoveMember:
+			if change.Subject == actor {
+				return Errorf("%s: refusing to remove %s, the user running sync", change.Group, actor)
+			}
+			if change.From == RoleOwner {
+				owners[group]--
+				demoted[group] = change.Group
+			}
+		case ChangeAddMember:
+			if change.To == RoleOwner {
+				owners[group]++
+			}
+		}
+	}
+	for group, name := range demoted {
+		if owners[group] <= 0 {
+			return Errorf("%s: refusing a plan that leaves the group with no owners", name)
+		}
+	}
+	return nil
+}
+
+// DiffSpec returns the changes needed to make live match spec
+func DiffSpec(spec *Spec, live map[string]*LiveGroup) *Plan {
+	plan := &Plan{Changes: make([]Change, 0)}
+	for _, gs := range spec.Groups {
+		lg, exists := live[strings.ToLower(gs.Name)]
+		if !exists {
+			plan.Changes = append(plan.Changes, Change{Kind: ChangeCreateGroup, Group: gs.Name, Settings: gs.Settings})
+			lg = &LiveGroup{Group: Group{Name: gs.Name}}
+		} else {
+			plan.Changes = append(plan.Changes, diffSettings(gs, lg.Group)...)
+		}
+		plan.Changes = append(plan.Changes, diffRoles(gs, lg)...)
+	}
+	return plan
+}
+
+// diffSettings returns an update-setting change for each setting declared in gs that differs on group
+func diffSettings(gs GroupSpec, group Group) []Change {
+	current := group.Settings()
+	names := make([]string, 0, len(gs.Settings))
+	for name := range gs.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	changes := make([]Change, 0)
+	for _, name := range names {
+		if current[name] != gs.Settings[name] {
+			changes = append(changes, Change{Kind: ChangeUpdateSetting, Group: group.Name, GroupID: group.ID,
+				Subject: name, From: current[name], To: gs.Settings[name]})
+		}
+	}
+	return changes
+}
+
+// isUnconfirmed reports whether the subscription is still awaiting approval or confirmation
+func (mi MemberInfo) isUnconfirmed() bool {
+	return mi.Status == MemberStatusPendingApproval || mi.Status == MemberStatusNotConfirmed
+}
+
+// diffRoles returns the changes needed to give each member of lg the role declared in gs. Subscriptions awaiting
+// approval or confirmation are left alone, and undeclared members are only removed when gs.Prune is set.
+func diffRoles(gs GroupSpec, lg *LiveGroup) []Change {
+	want := gs.roles()
+	changes := make([]Change, 0)
+	seen := make(map[string]bool, len(lg.Members))
+	members := append([]MemberInfo(nil), lg.Members...)
+	sort.Slice(members, func(i, j int) bool { return strings.ToLower(members[i].Email) < strings.ToLower(members[j].Email) })
+	for _, member := range members {
+		email := strings.ToLower(member.Email)
+		seen[email] = true
+		if member.isUnconfirmed() {
+			continue
+		}
+		have := roleOf(member.ModStatus)
+		role, declared := want[email]
+		base := Change{Group: lg.Group.Name, GroupID: lg.Group.ID, Subject: email, From: have, MemberID: member.ID}
+		switch {
+		case !declared && gs.Members != nil && gs.Prune:
+			base.Kind = ChangeRemoveMember
+			changes = append(changes, base)
+		case !declared && have != RoleMember:
+			base.Kind, base.To = ChangeSetRole, RoleMember
+			changes = append(changes, base)
+		case declared && role != have:
+			base.Kind, base.To = ChangeSetRole, role
+			changes = append(changes, base)
+		}
+	}
+	emails := make([]string, 0, len(want))
+	for email := range want {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	for _, email := range emails {
+		if !seen[email] {
+			changes = append(changes, Change{Kind: ChangeAddMember, Group: lg.Group.Name, GroupID: lg.Group.ID,
+				Subject: email, To: want[email]})
+		}
+	}
+	return changes
+}
+
+// ChangeResult is the outcome of applying a Change, Err is nil when it succeeded
+type ChangeResult struct {
+	Change Change
+	Err    error
+}
+
+// ApplyPlan makes each change in plan in order. A failed change does not stop the rest being attempted, except that
+// changes to a group that could not be created are skipped.
+func (c *GroupsClient) ApplyPlan(plan *Plan) []ChangeResult {
+	results := make([]ChangeResult, 0, len(plan.Changes))
+	created := make(map[string]int)
+	for _, change := range plan.Changes {
+		if change.GroupID == 0 && change.Kind != ChangeCreateGroup {
+			id, ok := created[change.Group]
+			if !ok {
+				results = append(results, ChangeResult{change, Errorf("group %s was not created", change.Group)})
+				continue
+			}
+			change.GroupID = id
+		}
+		var err error
+		switch change.Kind {
+		case ChangeCreateGroup:
+			var group *Group
+			if group, err = c.CreateGroup(change.Group, change.Settings); err == nil {
+				created[change.Group] = group.ID
+			}
+		case ChangeUpdateSetting:
+			_, err = c.UpdateGroup(change.GroupID, GroupSettings{change.Subject: change.To})
+		case ChangeSetRole:
+			_, err = c.UpdateGroupMember(change.GroupID, change.MemberID, MemberUpdate{ModStatus: modStatusOf(change.To)})
+		case ChangeRemoveMember:
+			err = c.RemoveGroupMember(change.GroupID, change.MemberID)
+		case ChangeAddMember:
+			err = c.addMemberWithRole(change.GroupID, change.Subject, change.To)
+		default:
+			err = Errorf("ApplyPlan: unknown change %q", change.Kind)
+		}
+		results = append(results, ChangeResult{change, err})
+	}
+	return results
+}
+
+// addMemberWithRole adds email to groupId and, when role is not member, then makes them an owner or moderator
+func (c *GroupsClient) addMemberWithRole(groupId int, email string, role string) error {
+	if err := c.AddGroupMembers(groupId, []string{email}); err != nil {
+		return err
+	}
+	if role == RoleMember {
+		return nil
+	}
+	members, _, err := c.GetGroupMembers(groupId)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if strings.EqualFold(member.Email, email) {
+			_, err := c.UpdateGroupMember(groupId, member.ID, MemberUpdate{ModStatus: modStatusOf(role)})
+			return err
+		}
+	}
+	return Errorf("addMemberWithRole: %s added to groupId %d but not found in its members", email, groupId)
+}
@@ -0,0 +1,75 @@
+package groupsclient
+
+import (
+	"strings"
+	"testing"
+)
+
+func liveGroup(members ...MemberInfo) map[string]*LiveGroup {
+	return map[string]*LiveGroup{"team": {Group: Group{ID: 1, Name: "team"}, Members: members}}
+}
+
+func member(id int, email string, status ModStatus) MemberInfo {
+	return MemberInfo{ID: id, Email: email, ModStatus: status, Status: MemberStatusNormal}
+}
+
+func TestDiffSpecRemovesOnlyWithPrune(t *testing.T) {
+	live := liveGroup(member(1, "owner@example.com", ModStatusOwner), member(2, "a@example.com", ModStatusNone),
+		MemberInfo{ID: 3, Email: "new@example.com", Status: MemberStatusPendingApproval})
+	empty := []string{}
+	tests := []struct {
+		name  string
+		prune bool
+		want  []string
+	}{
+		{"without prune", false, nil},
+		{"with prune", true, []string{"- team: remove a@example.com (member)"}},
+	}
+	for _, tt := range tests {
+		spec := &Spec{Groups: []GroupSpec{{Name: "team", Owners: []string{"owner@example.com"}, Members: &empty, Prune: tt.prune}}}
+		plan := DiffSpec(spec, live)
+		got := make([]string, 0, len(plan.Changes))
+		for _, change := range plan.Changes {
+			got = append(got, change.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: DiffSpec() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOwnership(t *testing.T) {
+	live := liveGroup(member(1, "me@example.com", ModStatusOwner), member(2, "other@example.com", ModStatusOwner),
+		member(3, "mod@example.com", ModStatusModerator))
+	tests := []struct {
+		name    string
+		spec    GroupSpec
+		wantErr string
+	}{
+		{"unchanged", GroupSpec{Owners: []string{"me@example.com", "other@example.com"}, Moderators: []string{"mod@example.com"}}, ""},
+		{"demote other owner", GroupSpec{Owners: []string{"me@example.com"}, Moderators: []string{"mod@example.com"}}, ""},
+		{"demote actor", GroupSpec{Owners: []string{"other@example.com"}}, "refusing to demote me@example.com"},
+		{"remove actor", GroupSpec{Owners: []string{"other@example.com"}, Members: &[]string{}, Prune: true}, "refusing to remove me@example.com"},
+		{"hand over", GroupSpec{Owners: []string{"mod@example.com"}, Moderators: []string{"me@example.com"}}, "refusing to demote"},
+	}
+	for _, tt := range tests {
+		tt.spec.Name = "team"
+		plan := DiffSpec(&Spec{Groups: []GroupSpec{tt.spec}}, live)
+		err := plan.CheckOwnership("Me@example.com", live)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: CheckOwnership() = %v, want nil", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: CheckOwnership() = %v, want error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckOwnershipLastOwner(t *testing.T) {
+	live := liveGroup(member(1, "me@example.com", ModStatusModerator), member(2, "owner@example.com", ModStatusOwner))
+	plan := DiffSpec(&Spec{Groups: []GroupSpec{{Name: "team", Moderators: []string{"me@example.com"}}}}, live)
+	err := plan.CheckOwnership("me@example.com", live)
+	if err == nil || !strings.Contains(err.Error(), "no owners") {
+		t.Errorf("CheckOwnership() = %v, want error about no owners", err)
+	}
+}
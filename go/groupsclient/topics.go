@@ -0,0 +1,136 @@
+package groupsclient
+
+import (
+	. "fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TopicAction names a moderation action that can be taken on a topic in a group's archives
+type TopicAction string
+
+const (
+	LockTopicAction     TopicAction = "lock"
+	UnlockTopicAction   TopicAction = "unlock"
+	StickyTopicAction   TopicAction = "sticky"
+	UnstickyTopicAction TopicAction = "unsticky"
+	HashtagsTopicAction TopicAction = "hashtags"
+	MoveTopicAction     TopicAction = "move"
+	DeleteTopicAction   TopicAction = "delete"
+)
+
+// Permitted reports whether admin may take action on the topics of its group, each topic action needs EditArchives
+func (action TopicAction) Permitted(admin MemberInfo) bool {
+	switch action {
+	case LockTopicAction, UnlockTopicAction, StickyTopicAction, UnstickyTopicAction, HashtagsTopicAction,
+		MoveTopicAction, DeleteTopicAction:
+		return admin.Perms.EditArchives
+	default:
+		return false
+	}
+}
+
+// ParseTopicID returns the topic id in ref, which is either the id itself or the URL of the topic, e.g.
+// https://lists.example.org/g/main/topic/release-plans/101234567
+func ParseTopicID(ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil && id > 0 {
+		return id, nil
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Host == "" {
+		return 0, Errorf("ParseTopicID: %q is neither a topic id nor a topic URL", ref)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments {
+		if segment != "topic" {
+			continue
+		}
+		// the id is the last numeric segment after /topic/, a slug of the subject may come before it
+		for j := len(segments) - 1; j > i; j-- {
+			if id, err := strconv.Atoi(segments[j]); err == nil && id > 0 {
+				return id, nil
+			}
+		}
+	}
+	return 0, Errorf("ParseTopicID: %q is not a topic URL", ref)
+}
+
+// GetTopic gets topicId
+// https://groups.io/api#get-topic
+func (c *GroupsClient) GetTopic(topicId int) (*Topic, error) {
+	var topic Topic
+	if err := c.getJSON(Sprintf("/api/v1/gettopic?topic_id=%d", topicId), &topic); err != nil {
+		return nil, Errorf("GetTopic: topicId %d: %w", topicId, err)
+	}
+	return &topic, nil
+}
+
+// updateTopic sets the fields of topic in formData
+// https://groups.io/api#update-topic
+func (c *GroupsClient) updateTopic(topic Topic, formData url.Values) (*Topic, error) {
+	formData.Set("group_id", strconv.Itoa(topic.GroupID))
+	formData.Set("topic_id", strconv.Itoa(topic.ID))
+	var updated Topic
+	if err := c.postForm("/api/v1/updatetopic", formData, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// LockTopic stops, or with locked false allows again, replies to topic
+func (c *GroupsClient) LockTopic(topic Topic, locked bool) (*Topic, error) {
+	updated, err := c.updateTopic(topic, url.Values{"locked": {strconv.FormatBool(locked)}})
+	if err != nil {
+		return nil, Errorf("LockTopic: topicId %d: %w", topic.ID, err)
+	}
+	return updated, nil
+}
+
+// SetTopicSticky pins, or with sticky false unpins, topic to the top of the group's topic list
+func (c *GroupsClient) SetTopicSticky(topic Topic, sticky bool) (*Topic, error) {
+	updated, err := c.updateTopic(topic, url.Values{"is_sticky": {strconv.FormatBool(sticky)}})
+	if err != nil {
+		return nil, Errorf("SetTopicSticky: topicId %d: %w", topic.ID, err)
+	}
+	return updated, nil
+}
+
+// SetTopicHashtags replaces the hashtags of topic with hashtags, given with or without their leading #
+func (c *GroupsClient) SetTopicHashtags(topic Topic, hashtags []string) (*Topic, error) {
+	names := make([]string, 0, len(hashtags))
+	for _, hashtag := range hashtags {
+		names = append(names, strings.TrimPrefix(hashtag, "#"))
+	}
+	updated, err := c.updateTopic(topic, url.Values{"hashtags": {strings.Join(names, ",")}})
+	if err != nil {
+		return nil, Errorf("SetTopicHashtags: topicId %d: %w", topic.ID, err)
+	}
+	return updated, nil
+}
+
+// MoveTopic moves topic, with all of its messages, to the group destGroupId
+// https://groups.io/api#move-topic
+func (c *GroupsClient) MoveTopic(topic Topic, destGroupId int) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(topic.GroupID))
+	formData.Set("topic_id", strconv.Itoa(topic.ID))
+	formData.Set("dest_group_id", strconv.Itoa(destGroupId))
+	if err := c.postForm("/api/v1/movetopic", formData, nil); err != nil {
+		return Errorf("MoveTopic: topicId %d to groupId %d: %w", topic.ID, destGroupId, err)
+	}
+	return nil
+}
+
+// DeleteTopic deletes topic and all of its messages
+// https://groups.io/api#delete-topic
+func (c *GroupsClient) DeleteTopic(topic Topic) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(topic.GroupID))
+	formData.Set("topic_id", strconv.Itoa(topic.ID))
+	if err := c.postForm("/api/v1/deletetopic", formData, nil); err != nil {
+		return Errorf("DeleteTopic: topicId %d: %w", topic.ID, err)
+	}
+	return nil
+}
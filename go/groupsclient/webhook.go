@@ -0,0 +1,268 @@
+package groupsclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	. "fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookSignatureHeader carries the hex encoded HMAC-SHA256 of the request body, keyed by the webhook's secret
+const WebhookSignatureHeader = "X-Groupsio-Signature"
+
+// maxWebhookBody bounds the size of event payloads read by WebhookServer
+const maxWebhookBody = 10 << 20
+
+// Webhook event types sent by groups.io
+// https://groups.io/api#webhooks
+const (
+	EventMemberAdded   = "added_member"
+	EventMemberRemoved = "removed_member"
+	EventMemberChanged = "changed_member"
+	EventMessageHeld   = "pending_message"
+	EventNewTopic      = "created_topic"
+	EventNewMessage    = "new_message"
+)
+
+// WebhookEvent is a groups.io webhook payload. MemberInfo is set for member events, PendingMsg for held messages.
+type WebhookEvent struct {
+	ID         int         `json:"id"`
+	Object     string      `json:"object"`
+	Created    string      `json:"created"`
+	Action     string      `json:"action"`
+	Group      *Group      `json:"group,omitempty"`
+	MemberInfo *MemberInfo `json:"member_info,omitempty"`
+	PendingMsg *PendingMsg `json:"pending_message,omitempty"`
+	// Raw is the payload exactly as it was received
+	Raw json.RawMessage `json:"-"`
+}
+
+// GroupName returns the name of the group the event happened in, as well as the payload reports it
+func (e WebhookEvent) GroupName() string {
+	switch {
+	case e.Group != nil:
+		return e.Group.Name
+	case e.MemberInfo != nil:
+		return e.MemberInfo.GroupName
+	case e.PendingMsg != nil:
+		return e.PendingMsg.GroupName
+	default:
+		return ""
+	}
+}
+
+// WebhookHandler acts on a verified, decoded, webhook event
+type WebhookHandler interface {
+	HandleEvent(ctx context.Context, event WebhookEvent) error
+}
+
+// WebhookHandlerFunc adapts a function to a WebhookHandler
+type WebhookHandlerFunc func(ctx context.Context, event WebhookEvent) error
+
+func (f WebhookHandlerFunc) HandleEvent(ctx context.Context, event WebhookEvent) error {
+	return f(ctx, event)
+}
+
+// webhookSeenIDs is how many recent event IDs WebhookServer remembers to drop redelivered events
+const webhookSeenIDs = 1000
+
+// WebhookServer is an http.Handler that verifies the signature of each groups.io webhook request, decodes its event,
+// acknowledges it and then passes it to every one of Handlers in turn in the background. Handler failures are logged,
+// they are not reported to groups.io, so a redelivery never re-runs handlers that already succeeded. An event whose
+// ID was seen recently is acknowledged and dropped.
+type WebhookServer struct {
+	Secret   string
+	Handlers []WebhookHandler
+	Logger   *log.Logger
+
+	mu        sync.Mutex
+	seen      map[int]bool
+	seenOrder []int
+	pending   sync.WaitGroup
+}
+
+// VerifyWebhookSignature reports whether signature, as sent in WebhookSignatureHeader with or without a sha256=
+// prefix, is the HMAC-SHA256 of body keyed by secret
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// DecodeWebhookEvent decodes a webhook payload
+func DecodeWebhookEvent(body []byte) (WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return event, Errorf("DecodeWebhookEvent: %w", err)
+	}
+	if event.Action == "" {
+		return event, Errorf("DecodeWebhookEvent: payload has no action")
+	}
+	event.Raw = append(json.RawMessage(nil), body...)
+	return event, nil
+}
+
+func (s *WebhookServer) logf(format string, args ...interface{}) {
+	if s.Logger != nil {
+		s.Logger.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+func (s *WebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "could not read body", http.StatusBadRequest)
+		return
+	}
+	if !VerifyWebhookSignature(s.Secret, body, r.Header.Get(WebhookSignatureHeader)) {
+		s.logf("WebhookServer: rejected request from %s with a bad signature", r.RemoteAddr)
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return
+	}
+	event, err := DecodeWebhookEvent(body)
+	if err != nil {
+		s.logf("WebhookServer: %v", err)
+		http.Error(w, "bad payload", http.StatusBadRequest)
+		return
+	}
+	if !s.firstDelivery(event.ID) {
+		s.logf("WebhookServer: dropped redelivered %s event %d", event.Action, event.ID)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	// acknowledge before handling, handlers such as exec can outlast groups.io's delivery timeout
+	s.pending.Add(1)
+	go s.dispatch(event)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// firstDelivery records id and reports whether it was not among the recently seen event IDs. Events with no ID are
+// always treated as new.
+func (s *WebhookServer) firstDelivery(id int) bool {
+	if id == 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil {
+		s.seen = make(map[int]bool)
+	}
+	if s.seen[id] {
+		return false
+	}
+	s.seen[id] = true
+	s.seenOrder = append(s.seenOrder, id)
+	if len(s.seenOrder) > webhookSeenIDs {
+		delete(s.seen, s.seenOrder[0])
+		s.seenOrder = s.seenOrder[1:]
+	}
+	return true
+}
+
+// dispatch passes event to each handler, logging those that fail
+func (s *WebhookServer) dispatch(event WebhookEvent) {
+	defer s.pending.Done()
+	for _, h := range s.Handlers {
+		if err := h.HandleEvent(context.Background(), event); err != nil {
+			s.logf("WebhookServer: %s event %d: %v", event.Action, event.ID, err)
+		}
+	}
+}
+
+// Wait blocks until every event acknowledged so far has been through the handlers
+func (s *WebhookServer) Wait() {
+	s.pending.Wait()
+}
+
+// OnlyEvents wraps h so that it only sees events whose Action is one of actions, an empty list passes every event
+func OnlyEvents(actions []string, h WebhookHandler) WebhookHandler {
+	if len(actions) == 0 {
+		return h
+	}
+	return WebhookHandlerFunc(func(ctx context.Context, event WebhookEvent) error {
+		for _, action := range actions {
+			if strings.EqualFold(strings.TrimSpace(action), event.Action) {
+				return h.HandleEvent(ctx, event)
+			}
+		}
+		return nil
+	})
+}
+
+// LogWebhookHandler logs a one line summary of each event to logger
+func LogWebhookHandler(logger *log.Logger) WebhookHandler {
+	return WebhookHandlerFunc(func(ctx context.Context, event WebhookEvent) error {
+		switch {
+		case event.MemberInfo != nil:
+			logger.Printf("webhook: %s group %s member %s", event.Action, event.GroupName(), event.MemberInfo)
+		case event.PendingMsg != nil:
+			logger.Printf("webhook: %s group %s from %s subject %q", event.Action, event.GroupName(),
+				event.PendingMsg.SenderEmail, event.PendingMsg.Subject)
+		default:
+			logger.Printf("webhook: %s group %s event %d", event.Action, event.GroupName(), event.ID)
+		}
+		return nil
+	})
+}
+
+// ExecWebhookHandler runs command with args for each event, passing the raw payload on stdin and the event's action
+// and group in the GROUPSIO_EVENT and GROUPSIO_GROUP environment variables
+func ExecWebhookHandler(timeout time.Duration, command string, args ...string) WebhookHandler {
+	return WebhookHandlerFunc(func(ctx context.Context, event WebhookEvent) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, command, args...)
+		cmd.Stdin = bytes.NewReader(event.Raw)
+		cmd.Env = append(os.Environ(), "GROUPSIO_EVENT="+event.Action, "GROUPSIO_GROUP="+event.GroupName())
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return Errorf("ExecWebhookHandler: %s: %w: %s", command, err, out)
+		}
+		return nil
+	})
+}
+
+// ForwardWebhookHandler POSTs the raw payload of each event to url, signing it with secret when secret is not empty
+func ForwardWebhookHandler(client *http.Client, url string, secret string) WebhookHandler {
+	return WebhookHandlerFunc(func(ctx context.Context, event WebhookEvent) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(event.Raw))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if secret != "" {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(event.Raw)
+			req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return Errorf("ForwardWebhookHandler: %s: %w", url, err)
+		}
+		defer checkClose(resp.Body.Close(), "ForwardWebhookHandler: Error closing resp.Body")
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return Errorf("ForwardWebhookHandler: %s: received non-2xx response code: %d", url, resp.StatusCode)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package groupsclient
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func sign(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := `{"action":"added_member"}`
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		want      bool
+	}{
+		{"valid", "s3cret", sign("s3cret", body), true},
+		{"sha256 prefix", "s3cret", "sha256=" + sign("s3cret", body), true},
+		{"surrounding space", "s3cret", " " + sign("s3cret", body) + "\n", true},
+		{"wrong secret", "other", sign("s3cret", body), false},
+		{"signed other body", "s3cret", sign("s3cret", body+" "), false},
+		{"not hex", "s3cret", "zz", false},
+		{"empty", "s3cret", "", false},
+	}
+	for _, tt := range tests {
+		if got := VerifyWebhookSignature(tt.secret, []byte(body), tt.signature); got != tt.want {
+			t.Errorf("%s: VerifyWebhookSignature() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookServer(t *testing.T) {
+	var mu sync.Mutex
+	handled := make([]int, 0)
+	server := &WebhookServer{
+		Secret: "s3cret",
+		Logger: log.New(io.Discard, "", 0),
+		Handlers: []WebhookHandler{
+			WebhookHandlerFunc(func(ctx context.Context, event WebhookEvent) error {
+				return errors.New("downstream failed")
+			}),
+			WebhookHandlerFunc(func(ctx context.Context, event WebhookEvent) error {
+				mu.Lock()
+				defer mu.Unlock()
+				handled = append(handled, event.ID)
+				return nil
+			}),
+		},
+	}
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		want      int
+	}{
+		{"bad signature", `{"id":1,"action":"added_member"}`, sign("wrong", `{"id":1,"action":"added_member"}`), http.StatusUnauthorized},
+		{"no action", `{"id":1}`, sign("s3cret", `{"id":1}`), http.StatusBadRequest},
+		{"acknowledged despite a failing handler", `{"id":1,"action":"added_member"}`, sign("s3cret", `{"id":1,"action":"added_member"}`), http.StatusNoContent},
+		{"redelivery", `{"id":1,"action":"added_member"}`, sign("s3cret", `{"id":1,"action":"added_member"}`), http.StatusNoContent},
+		{"next event", `{"id":2,"action":"removed_member"}`, sign("s3cret", `{"id":2,"action":"removed_member"}`), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+		req.Header.Set(WebhookSignatureHeader, tt.signature)
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+	server.Wait()
+	if len(handled) != 2 || handled[0]+handled[1] != 3 {
+		t.Errorf("handled events %v, want 1 and 2 once each", handled)
+	}
+}
@@ -0,0 +1,187 @@
+package groupsclient
+
+import (
+	. "fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Webhook is a registration that has groups.io POST events in a group to URL
+// https://groups.io/api#webhooks
+type Webhook struct {
+	ID      int      `json:"id"`
+	Object  string   `json:"object"`
+	Created string   `json:"created"`
+	Updated string   `json:"updated"`
+	GroupID int      `json:"group_id"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	// Secret signs each delivery, see VerifyWebhookSignature
+	Secret string `json:"secret" sensitive:"true"`
+}
+
+// webhookEvents are the event types a webhook can be registered for
+var webhookEvents = []string{EventMemberAdded, EventMemberRemoved, EventMemberChanged, EventMessageHeld, EventNewTopic,
+	EventNewMessage}
+
+// Validate checks hook has an absolute http(s) URL and only known event types
+func (hook Webhook) Validate() error {
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return Errorf("Webhook: %q is not an http or https URL", hook.URL)
+	}
+	if len(hook.Events) == 0 {
+		return Errorf("Webhook: %s has no events", hook.URL)
+	}
+	for _, event := range hook.Events {
+		if !slices.Contains(webhookEvents, event) {
+			return Errorf("Webhook: unknown event %q, must be one of %s", event, strings.Join(webhookEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// Matches reports whether hook already has the URL, events and secret of want, ignoring the order of events. The
+// secrets are only compared when both are set, groups.io may not echo a webhook's secret back.
+func (hook Webhook) Matches(want Webhook) bool {
+	if hook.URL != want.URL || len(hook.Events) != len(want.Events) {
+		return false
+	}
+	if hook.Secret != "" && want.Secret != "" && hook.Secret != want.Secret {
+		return false
+	}
+	for _, event := range want.Events {
+		if !slices.Contains(hook.Events, event) {
+			return false
+		}
+	}
+	return true
+}
+
+func (hook Webhook) formValues() url.Values {
+	formData := url.Values{}
+	formData.Set("url", hook.URL)
+	formData.Set("events", strings.Join(hook.Events, ","))
+	if hook.Secret != "" {
+		formData.Set("secret", hook.Secret)
+	}
+	return formData
+}
+
+// GetWebhooks returns the webhooks registered on groupId
+// https://groups.io/api#get-webhooks
+func (c *GroupsClient) GetWebhooks(groupId int) ([]Webhook, error) {
+	hooks, _, err := getAllPages[Webhook](c, Sprintf("/api/v1/getwebhooks?group_id=%d", groupId))
+	if err != nil {
+		return nil, Errorf("GetWebhooks: groupId %d: %w", groupId, err)
+	}
+	return hooks, nil
+}
+
+// CreateWebhook registers hook on groupId
+// https://groups.io/api#add-webhook
+func (c *GroupsClient) CreateWebhook(groupId int, hook Webhook) (*Webhook, error) {
+	if err := hook.Validate(); err != nil {
+		return nil, err
+	}
+	formData := hook.formValues()
+	formData.Set("group_id", strconv.Itoa(groupId))
+	var created Webhook
+	if err := c.postForm("/api/v1/addwebhook", formData, &created); err != nil {
+		return nil, Errorf("CreateWebhook: groupId %d %s: %w", groupId, hook.URL, err)
+	}
+	return &created, nil
+}
+
+// UpdateWebhook changes the URL, events and secret of the webhook hook.ID on hook.GroupID
+// https://groups.io/api#update-webhook
+func (c *GroupsClient) UpdateWebhook(hook Webhook) (*Webhook, error) {
+	if err := hook.Validate(); err != nil {
+		return nil, err
+	}
+	formData := hook.formValues()
+	formData.Set("group_id", strconv.Itoa(hook.GroupID))
+	formData.Set("webhook_id", strconv.Itoa(hook.ID))
+	var updated Webhook
+	if err := c.postForm("/api/v1/updatewebhook", formData, &updated); err != nil {
+		return nil, Errorf("UpdateWebhook: groupId %d webhookId %d: %w", hook.GroupID, hook.ID, err)
+	}
+	return &updated, nil
+}
+
+// DeleteWebhook removes the webhook webhookId from groupId
+// https://groups.io/api#delete-webhook
+func (c *GroupsClient) DeleteWebhook(groupId int, webhookId int) error {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(groupId))
+	formData.Set("webhook_id", strconv.Itoa(webhookId))
+	if err := c.postForm("/api/v1/deletewebhook", formData, nil); err != nil {
+		return Errorf("DeleteWebhook: groupId %d webhookId %d: %w", groupId, webhookId, err)
+	}
+	return nil
+}
+
+// WebhookAction is what RegisterWebhook did, or would do, to a group
+type WebhookAction string
+
+const (
+	WebhookCreated   WebhookAction = "create"
+	WebhookUpdated   WebhookAction = "update"
+	WebhookUnchanged WebhookAction = "unchanged"
+)
+
+// WebhookResult is the outcome of registering a webhook on one group
+type WebhookResult struct {
+	Group   Group
+	Action  WebhookAction
+	Webhook *Webhook
+	Err     error
+}
+
+// RegisterWebhook makes sure hook is registered on each of groups, creating it where no webhook has its URL and
+// updating the events and secret of the one that does. When dryRun is set only the Action of each result is filled in.
+func (c *GroupsClient) RegisterWebhook(groups []Group, hook Webhook, dryRun bool) []WebhookResult {
+	results := make([]WebhookResult, 0, len(groups))
+	if err := hook.Validate(); err != nil {
+		for _, group := range groups {
+			results = append(results, WebhookResult{Group: group, Err: err})
+		}
+		return results
+	}
+	for _, group := range groups {
+		result := WebhookResult{Group: group, Action: WebhookCreated}
+		existing, err := c.GetWebhooks(group.ID)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		want := hook
+		want.GroupID = group.ID
+		for _, have := range existing {
+			if have.URL != hook.URL {
+				continue
+			}
+			have := have
+			result.Webhook = &have
+			result.Action = WebhookUpdated
+			want.ID = have.ID
+			if have.Matches(hook) {
+				result.Action = WebhookUnchanged
+			}
+			break
+		}
+		if !dryRun {
+			switch result.Action {
+			case WebhookCreated:
+				result.Webhook, result.Err = c.CreateWebhook(group.ID, want)
+			case WebhookUpdated:
+				result.Webhook, result.Err = c.UpdateWebhook(want)
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,162 @@
+package groupsclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	. "fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WikiPage is a page of a group's wiki
+// https://groups.io/api#the-wiki-page-object
+type WikiPage struct {
+	ID             int    `json:"id"`
+	Object         string `json:"object"`
+	Created        string `json:"created"`
+	Updated        string `json:"updated"`
+	GroupID        int    `json:"group_id"`
+	Name           string `json:"name"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	LastEditorID   int    `json:"last_editor_id"`
+	LastEditorName string `json:"last_editor_name"`
+}
+
+// GetWiki returns every page of the wiki of groupId
+// https://groups.io/api#get-wiki
+func (c *GroupsClient) GetWiki(groupId int) ([]WikiPage, error) {
+	pages, _, err := getAllPages[WikiPage](c, Sprintf("/api/v1/getwiki?group_id=%d", groupId))
+	if err != nil {
+		return nil, Errorf("GetWiki: groupId %d: %w", groupId, err)
+	}
+	return pages, nil
+}
+
+// UpdateWikiPage changes the title and body of the page page.ID of page.GroupID to those of page
+// https://groups.io/api#update-wiki
+func (c *GroupsClient) UpdateWikiPage(page WikiPage) (*WikiPage, error) {
+	formData := url.Values{}
+	formData.Set("group_id", strconv.Itoa(page.GroupID))
+	formData.Set("page_id", strconv.Itoa(page.ID))
+	formData.Set("title", page.Title)
+	formData.Set("body", page.Body)
+	var updated WikiPage
+	if err := c.postForm("/api/v1/updatewiki", formData, &updated); err != nil {
+		return nil, Errorf("UpdateWikiPage: groupId %d pageId %d: %w", page.GroupID, page.ID, err)
+	}
+	return &updated, nil
+}
+
+// WikiFrontMatter heads an exported wiki page. Updated is when the page last changed on groups.io as of the export,
+// it is how PlanWikiImport tells that the page has been edited there since. Sha256 is the checksum of the title and
+// body as exported, it is how PlanWikiImport tells that the file has been edited.
+type WikiFrontMatter struct {
+	ID         int    `yaml:"id"`
+	Title      string `yaml:"title"`
+	LastEditor string `yaml:"last_editor,omitempty"`
+	Updated    string `yaml:"updated"`
+	Sha256     string `yaml:"sha256"`
+}
+
+// frontMatterDelimiter opens and closes the front matter of an exported wiki page
+const frontMatterDelimiter = "---\n"
+
+// normalizeWikiBody gives body \n line endings, so that a page is compared the same way however its line endings
+// were stored or edited
+func normalizeWikiBody(body string) string {
+	return strings.ReplaceAll(body, "\r\n", "\n")
+}
+
+// wikiChecksum is the Sha256 of the front matter of a page with title and body
+func wikiChecksum(title string, body string) string {
+	sum := sha256.Sum256([]byte(title + "\n" + normalizeWikiBody(body)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Markdown renders page as a Markdown file, front matter followed by the body of the page as groups.io stores it
+func (page WikiPage) Markdown() ([]byte, error) {
+	fm, err := yaml.Marshal(WikiFrontMatter{
+		ID:         page.ID,
+		Title:      page.Title,
+		LastEditor: page.LastEditorName,
+		Updated:    page.Updated,
+		Sha256:     wikiChecksum(page.Title, page.Body),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString(frontMatterDelimiter)
+	b.Write(fm)
+	b.WriteString(frontMatterDelimiter)
+	b.WriteString(page.Body)
+	return b.Bytes(), nil
+}
+
+// ParseWikiMarkdown splits a file written by WikiPage.Markdown into its front matter and body
+func ParseWikiMarkdown(data []byte) (WikiFrontMatter, string, error) {
+	var fm WikiFrontMatter
+	s := normalizeWikiBody(string(data))
+	if !strings.HasPrefix(s, frontMatterDelimiter) {
+		return fm, "", Errorf("ParseWikiMarkdown: no front matter")
+	}
+	header, body, found := strings.Cut(s[len(frontMatterDelimiter):], "\n"+frontMatterDelimiter)
+	if !found {
+		return fm, "", Errorf("ParseWikiMarkdown: front matter is not closed by ---")
+	}
+	if err := yaml.Unmarshal([]byte(header), &fm); err != nil {
+		return fm, "", Errorf("ParseWikiMarkdown: front matter: %w", err)
+	}
+	if fm.ID == 0 {
+		return fm, "", Errorf("ParseWikiMarkdown: front matter has no page id")
+	}
+	return fm, body, nil
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// WikiFileName is the name of the file page is exported to, a slug of its name or title
+func (page WikiPage) WikiFileName() string {
+	name := page.Name
+	if name == "" {
+		name = page.Title
+	}
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = Sprintf("page-%d", page.ID)
+	}
+	return slug + ".md"
+}
+
+// WikiImportAction is what importing an exported page does, or would do, on groups.io
+type WikiImportAction string
+
+const (
+	WikiUnchanged WikiImportAction = "unchanged"
+	WikiUpdate    WikiImportAction = "update"
+	WikiConflict  WikiImportAction = "conflict"
+)
+
+// PlanWikiImport decides what importing the exported page with front matter fm and body does given remote, the page
+// as it is now on groups.io. Only a file edited since it was exported is imported, and it is a conflict when the page
+// has also changed on groups.io since. Files exported without a checksum count as edited when they differ from remote.
+func PlanWikiImport(fm WikiFrontMatter, body string, remote WikiPage) WikiImportAction {
+	body = normalizeWikiBody(body)
+	if fm.Title == remote.Title && body == normalizeWikiBody(remote.Body) {
+		return WikiUnchanged
+	}
+	if fm.Sha256 != "" && wikiChecksum(fm.Title, body) == fm.Sha256 {
+		// not edited since the export, whatever changed did so on groups.io
+		return WikiUnchanged
+	}
+	if fm.Updated != remote.Updated {
+		return WikiConflict
+	}
+	return WikiUpdate
+}
@@ -0,0 +1,90 @@
+package groupsclient
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWikiMarkdownRoundTrip(t *testing.T) {
+	page := WikiPage{ID: 12, Title: "Getting: started", LastEditorName: "Ann", Updated: "2024-05-01T10:00:00Z",
+		Body: "# Welcome\r\n---\r\nsecond section\n"}
+	data, err := page.Markdown()
+	if err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	fm, body, err := ParseWikiMarkdown(data)
+	if err != nil {
+		t.Fatalf("ParseWikiMarkdown() error = %v", err)
+	}
+	if fm.ID != page.ID || fm.Title != page.Title || fm.LastEditor != page.LastEditorName || fm.Updated != page.Updated {
+		t.Errorf("ParseWikiMarkdown() front matter = %+v, want that of %+v", fm, page)
+	}
+	if body != normalizeWikiBody(page.Body) {
+		t.Errorf("ParseWikiMarkdown() body = %q, want %q", body, normalizeWikiBody(page.Body))
+	}
+	if got := PlanWikiImport(fm, body, page); got != WikiUnchanged {
+		t.Errorf("PlanWikiImport() of an unedited export = %s, want %s", got, WikiUnchanged)
+	}
+}
+
+func TestParseWikiMarkdownErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"no front matter", "# Title\n", "no front matter"},
+		{"unclosed", "---\nid: 1\ntitle: x\n", "not closed"},
+		{"bad yaml", "---\nid: [\n---\nbody", "front matter:"},
+		{"no id", "---\ntitle: x\n---\nbody", "no page id"},
+	}
+	for _, tt := range tests {
+		_, _, err := ParseWikiMarkdown([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: ParseWikiMarkdown() error = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPlanWikiImport(t *testing.T) {
+	exported := WikiPage{ID: 1, Title: "Rules", Body: "be kind\n", Updated: "2024-01-01T00:00:00Z"}
+	fm := WikiFrontMatter{ID: 1, Title: exported.Title, Updated: exported.Updated, Sha256: wikiChecksum(exported.Title, exported.Body)}
+	changedRemote := exported
+	changedRemote.Body, changedRemote.Updated = "be very kind\n", "2024-02-01T00:00:00Z"
+	tests := []struct {
+		name   string
+		fm     WikiFrontMatter
+		body   string
+		remote WikiPage
+		want   WikiImportAction
+	}{
+		{"unchanged", fm, exported.Body, exported, WikiUnchanged},
+		{"CRLF only", fm, "be kind\r\n", exported, WikiUnchanged},
+		{"edited locally", fm, "be kinder\n", exported, WikiUpdate},
+		{"edited on groups.io only", fm, exported.Body, changedRemote, WikiUnchanged},
+		{"edited on both", fm, "be kinder\n", changedRemote, WikiConflict},
+		{"no checksum, differs", WikiFrontMatter{ID: 1, Title: "Rules", Updated: exported.Updated}, "be kinder\n", exported, WikiUpdate},
+	}
+	for _, tt := range tests {
+		if got := PlanWikiImport(tt.fm, tt.body, tt.remote); got != tt.want {
+			t.Errorf("%s: PlanWikiImport() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWikiFileName(t *testing.T) {
+	tests := []struct {
+		page WikiPage
+		want string
+	}{
+		{WikiPage{ID: 1, Name: "Getting Started"}, "getting-started.md"},
+		{WikiPage{ID: 2, Title: "FAQ: Mail & Digests!"}, "faq-mail-digests.md"},
+		{WikiPage{ID: 3, Title: "../../etc/passwd"}, "etc-passwd.md"},
+		{WikiPage{ID: 4, Title: "日本語"}, "page-4.md"},
+	}
+	for _, tt := range tests {
+		if got := tt.page.WikiFileName(); got != tt.want {
+			t.Errorf("WikiFileName(%+v) = %q, want %q", tt.page, got, tt.want)
+		}
+	}
+}
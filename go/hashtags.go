@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+// hashtagsList prints the hashtags of the subgroup name
+func hashtagsList(client *groupsclient.GroupsClient, name string) error {
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	hashtags, err := client.GetHashtags(group.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("hashtagsList: %s has %d hashtag(s)\n", group.Name, len(hashtags))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCOLOR\tMODERATED\tNO EMAIL\tEXPIRE DAYS\tDESCRIPTION")
+	for _, h := range hashtags {
+		fmt.Fprintf(w, "#%s\t%s\t%t\t%t\t%d\t%s\n", h.Name, h.Color, h.Moderated, h.NoEmail, h.ExpireDays, h.Description)
+	}
+	return w.Flush()
+}
+
+// hashtagCreate defines the hashtag called hashtag in the subgroup name with settings, recording it in audit
+func hashtagCreate(client *groupsclient.GroupsClient, subs []groupsclient.MemberInfo, actor string, name string,
+	hashtag string, settings groupsclient.HashtagSettings, audit *AuditLog) error {
+	sub, err := adminSubFor(subs, name, groupsclient.CreateHashtagAction.Permitted)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("hashtagCreate: creating #%s in %s with settings\n", hashtag, sub.GroupName)
+	printSettings(groupsclient.GroupSettings(settings))
+	ContinuePrompt()
+	_, err = client.CreateHashtag(sub.GroupID, hashtag, settings)
+	recordAuditResult(audit, hashtagAuditEntry(actor, groupsclient.HashtagChange{Action: groupsclient.CreateHashtagAction,
+		GroupID: sub.GroupID, GroupName: sub.GroupName, Name: hashtag, Settings: settings}, ""), err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("hashtagCreate: created #%s in %s\n", hashtag, sub.GroupName)
+	return nil
+}
+
+// hashtagUpdate applies settings to the hashtag called hashtag in the subgroup name after showing how each will
+// change, recording it in audit
+func hashtagUpdate(client *groupsclient.GroupsClient, subs []groupsclient.MemberInfo, actor string, name string,
+	hashtag string, settings groupsclient.HashtagSettings, audit *AuditLog) error {
+	if len(settings) == 0 {
+		return fmt.Errorf("no settings given, use -set name=value")
+	}
+	sub, err := adminSubFor(subs, name, groupsclient.UpdateHashtagAction.Permitted)
+	if err != nil {
+		return err
+	}
+	existing, err := client.FindHashtag(sub.GroupID, hashtag)
+	if err != nil {
+		return err
+	}
+	current := existing.Settings()
+	fmt.Printf("hashtagUpdate: changing #%s in %s\n", existing.Name, sub.GroupName)
+	names := make([]string, 0, len(settings))
+	for setting := range settings {
+		names = append(names, setting)
+	}
+	sort.Strings(names)
+	for _, setting := range names {
+		fmt.Printf("  %s: %q -> %q\n", setting, current[setting], settings[setting])
+	}
+	ContinuePrompt()
+	_, err = client.UpdateHashtag(sub.GroupID, existing.ID, settings)
+	recordAuditResult(audit, hashtagAuditEntry(actor, groupsclient.HashtagChange{Action: groupsclient.UpdateHashtagAction,
+		GroupID: sub.GroupID, GroupName: sub.GroupName, Name: existing.Name, HashtagID: existing.ID,
+		Settings: settings, From: current}, ""), err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("hashtagUpdate: updated #%s in %s\n", existing.Name, sub.GroupName)
+	return nil
+}
+
+// hashtagDelete removes the hashtag called hashtag from the subgroup name, recording it in audit
+func hashtagDelete(client *groupsclient.GroupsClient, subs []groupsclient.MemberInfo, actor string, name string,
+	hashtag string, audit *AuditLog) error {
+	sub, err := adminSubFor(subs, name, groupsclient.DeleteHashtagAction.Permitted)
+	if err != nil {
+		return err
+	}
+	existing, err := client.FindHashtag(sub.GroupID, hashtag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("hashtagDelete: deleting #%s from %s, topics tagged with it lose the tag\n", existing.Name, sub.GroupName)
+	ContinuePrompt()
+	err = client.DeleteHashtag(sub.GroupID, existing.ID)
+	recordAuditResult(audit, hashtagAuditEntry(actor, groupsclient.HashtagChange{Action: groupsclient.DeleteHashtagAction,
+		GroupID: sub.GroupID, GroupName: sub.GroupName, Name: existing.Name, HashtagID: existing.ID}, ""), err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("hashtagDelete: deleted #%s from %s\n", existing.Name, sub.GroupName)
+	return nil
+}
+
+// hashtagAuditEntry describes change, made by actor for reason, in the audit log
+func hashtagAuditEntry(actor string, change groupsclient.HashtagChange, reason string) AuditEntry {
+	return AuditEntry{
+		Actor:     actor,
+		Action:    "hashtag " + string(change.Action),
+		GroupID:   change.GroupID,
+		GroupName: change.GroupName,
+		Target:    change.String(),
+		Reason:    reason,
+	}
+}
+
+// hashtagsSync plans the changes needed to make the hashtags of each subgroup matching re match the set at setPath,
+// prints the plan and, when confirm is set, applies it, recording each change in audit. Changes actor lacks the
+// Perms for are reported and skipped. Returns the exit code of the command.
+func hashtagsSync(client *groupsclient.GroupsClient, actor string, subs []groupsclient.MemberInfo, re string,
+	setPath string, confirm bool, audit *AuditLog) int {
+	set, err := groupsclient.LoadHashtagSet(setPath)
+	if err != nil {
+		fmt.Printf("hashtagsSync: %v\n", err)
+		return exitError
+	}
+	targets, err := postTargets(subs, "", re)
+	if err != nil {
+		fmt.Printf("hashtagsSync: %v\n", err)
+		return exitError
+	}
+	changes, err := client.PlanHashtagSync(set, targets)
+	if err != nil {
+		fmt.Printf("hashtagsSync: Error fetching hashtags: %v\n", err)
+		return exitError
+	}
+	if len(changes) == 0 {
+		fmt.Printf("hashtagsSync: %d group(s) have the %d hashtag(s) in %s, nothing to do\n", len(targets), len(set.Hashtags), setPath)
+		return exitInSync
+	}
+	fmt.Printf("hashtagsSync: %d change(s) needed\n", len(changes))
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if !confirm {
+		fmt.Println("hashtagsSync: run again with --confirm to apply")
+		return exitDrift
+	}
+
+	failed := 0
+	for _, change := range changes {
+		sub, _ := subFor(targets, change.GroupID)
+		if !change.Action.Permitted(sub) {
+			failed++
+			fmt.Printf("hashtagsSync: %s does not have permission to %s hashtags on %s, skipping #%s\n", actor, change.Action,
+				change.GroupName, change.Name)
+			continue
+		}
+		err := client.ApplyHashtagChange(change)
+		if err != nil {
+			failed++
+			fmt.Printf("hashtagsSync: FAILED %s: %v\n", change, err)
+		} else {
+			fmt.Printf("hashtagsSync: applied %s\n", change)
+		}
+		recordAuditResult(audit, hashtagAuditEntry(actor, change, setPath), err)
+	}
+	if failed > 0 {
+		fmt.Printf("hashtagsSync: %d of %d change(s) failed\n", failed, len(changes))
+		return exitError
+	}
+	return exitInSync
+}
@@ -4,24 +4,28 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"main/groupsclient"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // filterSrcUserSubs takes a regular expression in re and returns an array of MemberInfo whose GroupName filed
 // matches the regular expression in filter
-func filterSrcUserSubs(re string, subs []groupsclient.MemberInfo) (int, []groupsclient.MemberInfo) {
-	filteredCount := 0
+func filterSrcUserSubs(re string, subs []groupsclient.MemberInfo) ([]groupsclient.MemberInfo, error) {
+	subsRegExp, err := regexp.Compile(re)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --filter %q: %w", re, err)
+	}
 	filteredList := make([]groupsclient.MemberInfo, 0)
-	var subsRegExp = regexp.MustCompile(re)
 	for _, sub := range subs {
 		if subsRegExp.MatchString(sub.GroupName) {
 			filteredList = append(filteredList, sub)
 		}
 	}
-	return filteredCount, filteredList
+	return filteredList, nil
 }
 
 func main() {
@@ -29,10 +33,71 @@ func main() {
 	emailPtr := flag.String("srcEmail", "", "groups.io email of the source user")
 	passwordPtr := flag.String("srcPass", "", "groups.io password of the source user")
 	listFilterPtr := flag.String("filter", "", "RegEx to filter the lists of subscriptions that the command will work on")
-	cmdPtr := flag.String("cmd", "view", "Can be one of: srcUserSubs, getUser, xferSubs or pendMsgs")
+	cmdPtr := flag.String("cmd", "view", "Can be one of: srcUserSubs, getUser, xferSubs, pendMsgs, membersRemove, membersBan, membersSet, pendReview, pendAutoMod, pendMembers, pendMembersReview, groupsList, groupCreate, groupUpdate, groupDelete, sync, export, drift, auditOwners, orgShow, orgUpdate, serveWebhooks, webhooksList, webhookCreate, webhookUpdate, webhookDelete, webhooksRegister, archiveSearch, archiveExport, post, topicLock, topicUnlock, topicSticky, topicUnsticky, topicHashtags, topicMove, topicDelete, hashtagsList, hashtagCreate, hashtagUpdate, hashtagDelete, hashtagsSync, filesList, filesUpload, filesDownload, filesDelete, filesMirror, wikiExport or wikiImport")
 	destEmailPtr := flag.String("destEmail", "", "email of user who will acquire your subscriptions and permissions on groups.io")
+	memberEmailPtr := flag.String("memberEmail", "", "email of the member that the command will act on")
+	emailsFilePtr := flag.String("emailsFile", "", "file of member emails, one per line, that the command will act on")
+	reasonPtr := flag.String("reason", "", "reason for the action, recorded in the audit log")
+	auditLogPtr := flag.String("auditLog", "groups-admin-audit.log", "file that administrative actions are recorded in")
+	minAgePtr := flag.Duration("minAge", 0, "pendMsgs, pendReview: only pending messages held for at least this long, e.g. 24h")
+	maxAgePtr := flag.Duration("maxAge", 0, "pendMsgs, pendReview: only pending messages held for at most this long")
+	senderPtr := flag.String("sender", "", "pendMsgs, pendReview: only pending messages whose sender's email or name contains this")
+	rulesPtr := flag.String("rules", "", "pendAutoMod: JSON file of moderation rules")
+	dryRunPtr := flag.Bool("dryRun", false, "show what would be done without changing anything on groups.io")
+	intervalPtr := flag.Duration("interval", 0, "pendAutoMod: re-run every interval, e.g. 10m, rather than once")
+	trustAllPtr := flag.Bool("trustAll", false, "pendReview: approve and trust unmoderates the sender in every group you manage, not just the one the message was held in")
+	trustedDomainsPtr := flag.String("trustedDomains", "", "pendMembersReview: comma separated email domains whose applicants are approved without review")
+	groupNamePtr := flag.String("groupName", "", "groupCreate, groupUpdate, groupDelete, webhooksList, webhookCreate, webhookUpdate, webhookDelete, archiveSearch, archiveExport, post, hashtag*, files*, wiki*: name of the subgroup. topicMove: subgroup to move the topic to")
+	likePtr := flag.String("like", "", "groupCreate: name of an existing subgroup whose settings the new group copies")
+	groupSettings := settingsFlag{}
+	flag.Var(groupSettings, "set", "groupCreate, groupUpdate, orgUpdate, hashtagCreate, hashtagUpdate: name=value setting, may be repeated")
+	specPtr := flag.String("spec", "", "sync, drift: YAML, or .json, file declaring the groups, settings and roles of the org. hashtagsSync: YAML, or .json, file declaring the standard hashtags")
+	confirmPtr := flag.Bool("confirm", false, "sync, hashtagsSync: apply the plan rather than only printing it. post: send without asking, required when the body is read from stdin")
+	outPtr := flag.String("out", "", "export, drift, auditOwners, archiveExport: file to write, stdout when not set. archiveExport maildir, filesMirror, wikiExport: directory to write. wikiImport: directory to read. filesDownload: file to write")
+	formatPtr := flag.String("format", "", "export: yaml or json, defaults to the extension of --out. drift: text, json or junit. auditOwners: text, csv or json. archiveExport: mbox, maildir or jsonl")
+	withMembersPtr := flag.Bool("withMembers", false, "export: list every member, not only owners and moderators")
+	flaggedOnlyPtr := flag.Bool("flaggedOnly", false, "auditOwners: only report groups with no, a single, or no reachable owners, or whose members could not be fetched. The report gives when each owner's membership last changed, groups.io does not report last activity")
+	listenPtr := flag.String("listen", ":8080", "serveWebhooks: address to listen on for webhook deliveries")
+	webhookSecretPtr := flag.String("webhookSecret", os.Getenv("GROUPSIO_WEBHOOK_SECRET"), "serveWebhooks, webhookCreate, webhookUpdate, webhooksRegister: secret that webhook deliveries are signed with, defaults to $GROUPSIO_WEBHOOK_SECRET")
+	eventsPtr := flag.String("events", "", "serveWebhooks: comma separated event actions passed to -onEvent and -forwardTo, all when not set. webhookCreate, webhookUpdate, webhooksRegister: event types the webhook is sent, e.g. added_member,pending_message")
+	onEventPtr := flag.String("onEvent", "", "serveWebhooks: command run for each event with the event JSON on stdin")
+	webhookUrlPtr := flag.String("webhookUrl", "", "webhookCreate, webhookUpdate, webhooksRegister: URL that groups.io delivers events to")
+	webhookIdPtr := flag.Int("webhookId", 0, "webhookUpdate, webhookDelete: id of the webhook, as shown by webhooksList")
+	forwardToPtr := flag.String("forwardTo", "", "serveWebhooks: URL each event is forwarded to")
+	forwardSecretPtr := flag.String("forwardSecret", os.Getenv("GROUPSIO_FORWARD_SECRET"), "serveWebhooks: secret events forwarded to -forwardTo are signed with, unsigned when not set, defaults to $GROUPSIO_FORWARD_SECRET")
+	afterPtr := flag.String("after", "", "archiveExport: only messages posted on or after this YYYY-MM-DD date")
+	beforePtr := flag.String("before", "", "archiveExport: only messages posted on or before this YYYY-MM-DD date")
+	hashtagPtr := flag.String("hashtag", "", "archiveExport: only messages in topics tagged with this hashtag. hashtagCreate, hashtagUpdate, hashtagDelete: name of the hashtag")
+	queryPtr := flag.String("query", "", "archiveSearch, archiveExport: search the archives for this")
+	subjectPtr := flag.String("subject", "", "post: subject of the message")
+	bodyFilePtr := flag.String("bodyFile", "", "post: file holding the body of the message, stdin when not set or - in which case --confirm is needed")
+	markdownPtr := flag.Bool("markdown", false, "post: the body is Markdown, implied by a .md bodyFile")
+	hashtagsPtr := flag.String("hashtags", "", "post: comma separated hashtags added to the subject. topicHashtags: comma separated hashtags that replace the topic's")
+	var attachments listFlag
+	flag.Var(&attachments, "attach", "post: file to attach, may be repeated")
+	topicPtr := flag.String("topic", "", "topic*: id or URL of the topic")
+	pathPtr := flag.String("path", "", "filesDownload, filesDelete: path of the file in the group's Files section. filesUpload: folder to upload into, the top level when not set")
+	localFilePtr := flag.String("localFile", "", "filesUpload: local file to upload")
+	showSecretsPtr := flag.Bool("showSecrets", false, "show secrets such as tokens and the SSO client secret rather than redacting them")
+	var updateFlags memberUpdateFlags
+	flag.StringVar(&updateFlags.modStatus, "modStatus", "", "membersSet: none, moderator or owner")
+	flag.StringVar(&updateFlags.postStatus, "postStatus", "", "membersSet: default, moderated, unmoderated, not_allowed or new_moderated")
+	flag.StringVar(&updateFlags.emailDelivery, "emailDelivery", "", "membersSet: single, digest, summary, special or none")
+	flag.StringVar(&updateFlags.messageSelection, "messageSelection", "", "membersSet: all or following_only")
+	flag.StringVar(&updateFlags.notify, "notify", "", "membersSet: comma separated notify settings, e.g. pending_msg_notify=email,sub_notify=none")
+	flag.StringVar(&updateFlags.modPermissions, "modPermissions", "", "membersSet: comma separated moderator permissions")
+	flag.StringVar(&updateFlags.extraMemberData, "extraMemberData", "", "membersSet: JSON array of extra member data")
 
 	flag.Parse()
+	groupsclient.SetShowSecrets(*showSecretsPtr)
+	if *cmdPtr == "serveWebhooks" {
+		// webhook deliveries are authenticated by their signature so serving them needs no groups.io login
+		if err := serveWebhooks(*listenPtr, *webhookSecretPtr, *eventsPtr, *onEventPtr, *forwardToPtr, *forwardSecretPtr); err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			os.Exit(exitError)
+		}
+		return
+	}
 	client := groupsclient.NewGroupsClient(*baseUrl)
 	// Authenticate and get the token
 	err := client.Authenticate(*emailPtr, *passwordPtr)
@@ -48,8 +113,9 @@ func main() {
 		fmt.Printf("main: Error getting user ID for %s: %v\n", *emailPtr, err)
 		return
 	}
-	fmt.Printf("userId of loggedInUser: %v\n", srcUser.ID)
-	fmt.Printf("FullName of loggedInUser: %v\n", srcUser.FullName)
+	// stderr so that commands such as export can write their output to stdout
+	fmt.Fprintf(os.Stderr, "userId of loggedInUser: %v\n", srcUser.ID)
+	fmt.Fprintf(os.Stderr, "FullName of loggedInUser: %v\n", srcUser.FullName)
 	switch *cmdPtr {
 	case "srcUserSubs":
 		// Get the list of subgroups where the existing user has Owner permissions
@@ -65,8 +131,12 @@ func main() {
 		}
 		if *listFilterPtr != "" {
 			fmt.Printf("main: Getting user groups for %s: filtered by %s\n", srcUser.FullName, *listFilterPtr)
-			filteredCount, filteredList := filterSrcUserSubs(*listFilterPtr, srcUsersSubs)
-			fullSummaryReport(emailPtr, filteredCount, filteredList)
+			filteredList, err := filterSrcUserSubs(*listFilterPtr, srcUsersSubs)
+			if err != nil {
+				fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+				os.Exit(exitError)
+			}
+			fullSummaryReport(emailPtr, len(filteredList), filteredList)
 		} else {
 			fullSummaryReport(emailPtr, subscriptionCount, srcUsersSubs)
 		}
@@ -101,7 +171,12 @@ func main() {
 		}
 		if *listFilterPtr != "" {
 			fmt.Printf("main: xferSubs: Getting user groups for %s: filtered by %s\n", srcUser.FullName, *listFilterPtr)
-			filteredCount, filteredList := filterSrcUserSubs(*listFilterPtr, srcUsersSubs)
+			filteredList, err := filterSrcUserSubs(*listFilterPtr, srcUsersSubs)
+			if err != nil {
+				fmt.Printf("main: xferSubs: %v\n", err)
+				os.Exit(exitError)
+			}
+			filteredCount := len(filteredList)
 			targetUserSubs, err := client.GrantOwnerPermsToGroupMember(*targetUser, filteredList)
 			if err != nil {
 				fmt.Printf("main: xferSubs: Error granting owner perms from %s to : filtered by %s %+v \n", srcUser.FullName, *targetUser, err)
@@ -117,17 +192,471 @@ func main() {
 		}
 
 	case "pendMsgs":
-
-		pendingMessages, count, err := client.GetPendingMsgList()
+		pendingMessages, err := filteredPendingMsgs(client, *listFilterPtr, *minAgePtr, *maxAgePtr, *senderPtr)
 		if err != nil {
-			fmt.Printf("groups-admin: Error returned by client.GetPendingMsgList(): %v \n", err)
+			fmt.Printf("groups-admin: Error getting pending messages: %v \n", err)
 			return
 		}
-		fmt.Printf("pendMsgs: found %d ON MAIN GROUP\n", count)
+		fmt.Printf("pendMsgs: found %d across the groups you moderate\n", len(pendingMessages))
 
-		//targetUserSubs, err := client.ReleasePendingEmail(listIds, allowedEmail)
 		for i, pendingMessage := range pendingMessages {
-			fmt.Printf("pendMsgs: %d, from: %+v, subject: %s\n", i, pendingMessage.Sender, pendingMessage.Subject)
+			fmt.Printf("pendMsgs: %d, %s\n", i, pendingMsgSummary(pendingMessage))
+		}
+	case "pendReview":
+		pendingMessages, err := filteredPendingMsgs(client, *listFilterPtr, *minAgePtr, *maxAgePtr, *senderPtr)
+		if err != nil {
+			fmt.Printf("groups-admin: Error getting pending messages: %v \n", err)
+			return
+		}
+		fmt.Printf("pendReview: %d message(s) pending\n", len(pendingMessages))
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			return
+		}
+		reviewPendingMsgs(client, srcUser.Email, pendingMessages, srcUsersSubs, *trustAllPtr, audit)
+	case "membersRemove", "membersBan":
+		action := groupsclient.RemoveMemberAction
+		if *cmdPtr == "membersBan" {
+			action = groupsclient.BanMemberAction
+		}
+		emails, err := targetEmails(*memberEmailPtr, *emailsFilePtr)
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			return
+		}
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			return
+		}
+		if *listFilterPtr != "" {
+			if srcUsersSubs, err = filterSrcUserSubs(*listFilterPtr, srcUsersSubs); err != nil {
+				fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+				os.Exit(exitError)
+			}
+		}
+		fmt.Printf("%s: will %s %d member(s) from up to %d group(s), reason: %q\n", *cmdPtr, action, len(emails), len(srcUsersSubs), *reasonPtr)
+		ContinuePrompt()
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		actioned := bulkMemberAction(client, action, srcUser.Email, emails, srcUsersSubs, *reasonPtr, audit)
+		fmt.Printf("%s: %d membership(s) actioned\n", *cmdPtr, actioned)
+	case "membersSet":
+		update, err := updateFlags.memberUpdate()
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			return
+		}
+		emails, err := targetEmails(*memberEmailPtr, *emailsFilePtr)
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			return
+		}
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			return
+		}
+		if *listFilterPtr != "" {
+			if srcUsersSubs, err = filterSrcUserSubs(*listFilterPtr, srcUsersSubs); err != nil {
+				fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+				os.Exit(exitError)
+			}
+		}
+		fmt.Printf("%s: will apply %+v to %d member(s) on up to %d group(s)\n", *cmdPtr, update, len(emails), len(srcUsersSubs))
+		ContinuePrompt()
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		updated := bulkMemberUpdate(client, update, srcUser.Email, emails, srcUsersSubs, *reasonPtr, audit)
+		fmt.Printf("%s: %d membership(s) updated\n", *cmdPtr, updated)
+	case "pendAutoMod":
+		rules, err := groupsclient.LoadModerationRules(*rulesPtr)
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			return
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		for {
+			err := autoModerate(client, srcUser.Email, rules, *listFilterPtr, *minAgePtr, *maxAgePtr, *senderPtr, *dryRunPtr, audit)
+			if err != nil {
+				fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			}
+			if *intervalPtr <= 0 {
+				break
+			}
+			time.Sleep(*intervalPtr)
+		}
+	case "pendMembers", "pendMembersReview":
+		pending, err := filteredPendingMembers(client, *listFilterPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error getting pending members: %v\n", *cmdPtr, err)
+			return
+		}
+		fmt.Printf("%s: %d member(s) awaiting approval\n", *cmdPtr, len(pending))
+		if *cmdPtr == "pendMembers" {
+			for i, member := range pending {
+				printPendingMember(i, len(pending), member)
+			}
+			return
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		if *trustedDomainsPtr != "" {
+			pending = autoApprovePendingMembers(client, srcUser.Email, pending, strings.Split(*trustedDomainsPtr, ","), *dryRunPtr, audit)
+		}
+		if !*dryRunPtr {
+			reviewPendingMembers(client, srcUser.Email, pending, audit)
+		}
+	case "groupsList":
+		groups, groupCount, err := client.ListGroups()
+		if err != nil {
+			fmt.Printf("main: %s: Error listing groups: %v\n", *cmdPtr, err)
+			return
+		}
+		groups, err = filterGroups(*listFilterPtr, groups)
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			return
+		}
+		fmt.Printf("%s: showing %d of the org's %d subgroups\n", *cmdPtr, len(groups), groupCount)
+		groupsReport(groups)
+	case "groupCreate", "groupUpdate", "groupDelete":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		switch *cmdPtr {
+		case "groupCreate":
+			err = groupCreate(client, srcUser.Email, *groupNamePtr, *likePtr, groupsclient.GroupSettings(groupSettings), audit)
+		case "groupUpdate":
+			err = groupUpdate(client, srcUser.Email, *groupNamePtr, groupsclient.GroupSettings(groupSettings), audit)
+		case "groupDelete":
+			var srcUsersSubs []groupsclient.MemberInfo
+			if srcUsersSubs, _, err = client.GetMemberInfoList(); err == nil {
+				err = groupDelete(client, srcUsersSubs, srcUser.Email, *groupNamePtr, audit)
+			}
+		}
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "sync":
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			os.Exit(exitError)
+		}
+		code := syncSpec(client, srcUser.Email, *specPtr, *confirmPtr, audit)
+		audit.Close()
+		os.Exit(code)
+	case "export":
+		if err := exportSpec(client, *listFilterPtr, *withMembersPtr, *outPtr, *formatPtr); err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "drift":
+		os.Exit(driftReport(client, *specPtr, *outPtr, *formatPtr))
+	case "auditOwners":
+		if err := auditOwners(client, *listFilterPtr, *flaggedOnlyPtr, *outPtr, *formatPtr); err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "orgShow":
+		org, err := client.GetOrg()
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			return
+		}
+		printOrgSettings(org)
+	case "orgUpdate":
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		if err := orgUpdate(client, srcUser.Email, groupsclient.OrgSettings(groupSettings), audit); err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "webhooksList", "webhookCreate", "webhookUpdate", "webhookDelete":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		hook := groupsclient.Webhook{URL: *webhookUrlPtr, Events: splitList(*eventsPtr), Secret: *webhookSecretPtr}
+		if *cmdPtr == "webhooksList" {
+			if err := webhooksList(client, *groupNamePtr); err != nil {
+				fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			}
+			return
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		switch *cmdPtr {
+		case "webhookCreate":
+			err = webhookCreate(client, srcUser.Email, *groupNamePtr, hook, audit)
+		case "webhookUpdate":
+			err = webhookUpdate(client, srcUser.Email, *groupNamePtr, *webhookIdPtr, hook, audit)
+		case "webhookDelete":
+			err = webhookDelete(client, srcUser.Email, *groupNamePtr, *webhookIdPtr, audit)
+		}
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "webhooksRegister":
+		hook := groupsclient.Webhook{URL: *webhookUrlPtr, Events: splitList(*eventsPtr), Secret: *webhookSecretPtr}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			os.Exit(exitError)
+		}
+		failed, err := webhooksRegister(client, srcUser.Email, *listFilterPtr, hook, *dryRunPtr, audit)
+		audit.Close()
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			os.Exit(exitError)
+		}
+		if failed > 0 {
+			os.Exit(exitError)
+		}
+	case "archiveSearch", "archiveExport":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		if *cmdPtr == "archiveSearch" {
+			err = archiveSearch(client, *groupNamePtr, *queryPtr)
+		} else {
+			var f groupsclient.ArchiveFilter
+			if f, err = archiveFilter(*afterPtr, *beforePtr, *hashtagPtr, *queryPtr); err == nil {
+				err = archiveExport(client, *groupNamePtr, f, *outPtr, *formatPtr)
+			}
+		}
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "post":
+		if readsStdin(*bodyFilePtr) && !*confirmPtr && !*dryRunPtr {
+			// the body uses up stdin so there would be nothing left to answer the confirmation prompt with
+			fmt.Printf("main: %s: --confirm or --dryRun must be specified when the body is read from stdin\n", *cmdPtr)
+			return
+		}
+		body, markdown, err := readPostBody(*bodyFilePtr, *markdownPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error reading body: %v\n", *cmdPtr, err)
+			return
+		}
+		post := groupsclient.Post{
+			Subject:     *subjectPtr,
+			Body:        body,
+			Markdown:    markdown,
+			Hashtags:    splitList(*hashtagsPtr),
+			Attachments: attachments,
+		}
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			return
+		}
+		targets, err := postTargets(srcUsersSubs, *groupNamePtr, *listFilterPtr)
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			os.Exit(exitError)
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		posted, err := postMessage(client, srcUser.Email, targets, post, *dryRunPtr, *confirmPtr, audit)
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			return
+		}
+		if !*dryRunPtr {
+			fmt.Printf("%s: posted to %d of %d group(s)\n", *cmdPtr, posted, len(targets))
+		}
+	case "topicLock", "topicUnlock", "topicSticky", "topicUnsticky", "topicHashtags", "topicMove", "topicDelete":
+		if *topicPtr == "" {
+			fmt.Printf("main: %s: --topic not specified.\n", *cmdPtr)
+			return
+		}
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			return
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		err = moderateTopic(client, srcUser.Email, topicActions[*cmdPtr], *topicPtr, splitList(*hashtagsPtr), *groupNamePtr,
+			*reasonPtr, srcUsersSubs, audit)
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "hashtagsList":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		if err := hashtagsList(client, *groupNamePtr); err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "hashtagCreate", "hashtagUpdate", "hashtagDelete":
+		if *groupNamePtr == "" || *hashtagPtr == "" {
+			fmt.Printf("main: %s: --groupName and --hashtag must be specified.\n", *cmdPtr)
+			return
+		}
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			return
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		settings := groupsclient.HashtagSettings(groupSettings)
+		switch *cmdPtr {
+		case "hashtagCreate":
+			err = hashtagCreate(client, srcUsersSubs, srcUser.Email, *groupNamePtr, *hashtagPtr, settings, audit)
+		case "hashtagUpdate":
+			err = hashtagUpdate(client, srcUsersSubs, srcUser.Email, *groupNamePtr, *hashtagPtr, settings, audit)
+		case "hashtagDelete":
+			err = hashtagDelete(client, srcUsersSubs, srcUser.Email, *groupNamePtr, *hashtagPtr, audit)
+		}
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+		}
+	case "hashtagsSync":
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			os.Exit(exitError)
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			os.Exit(exitError)
+		}
+		code := hashtagsSync(client, srcUser.Email, srcUsersSubs, *listFilterPtr, *specPtr, *confirmPtr, audit)
+		audit.Close()
+		os.Exit(code)
+	case "filesList", "filesDownload", "filesMirror":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		switch *cmdPtr {
+		case "filesList":
+			err = filesList(client, *groupNamePtr)
+		case "filesDownload":
+			err = filesDownload(client, *groupNamePtr, *pathPtr, *outPtr)
+		case "filesMirror":
+			var failed int
+			if failed, err = filesMirror(client, *groupNamePtr, *outPtr); err == nil && failed > 0 {
+				os.Exit(exitError)
+			}
+		}
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			os.Exit(exitError)
+		}
+	case "filesUpload", "filesDelete":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			return
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			return
+		}
+		defer audit.Close()
+		if *cmdPtr == "filesUpload" {
+			err = filesUpload(client, srcUsersSubs, srcUser.Email, *groupNamePtr, *pathPtr, *localFilePtr, audit)
+		} else {
+			err = filesDelete(client, srcUsersSubs, srcUser.Email, *groupNamePtr, *pathPtr, audit)
+		}
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			audit.Close()
+			os.Exit(exitError)
+		}
+	case "wikiExport":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		if err := wikiExport(client, *groupNamePtr, *outPtr); err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			os.Exit(exitError)
+		}
+	case "wikiImport":
+		if *groupNamePtr == "" {
+			fmt.Printf("main: %s: --groupName not specified.\n", *cmdPtr)
+			return
+		}
+		srcUsersSubs, _, err := client.GetMemberInfoList()
+		if err != nil {
+			fmt.Printf("main: %s: Error getting user groups for %s: %v\n", *cmdPtr, srcUser.FullName, err)
+			os.Exit(exitError)
+		}
+		audit, err := OpenAuditLog(*auditLogPtr)
+		if err != nil {
+			fmt.Printf("main: %s: Error opening audit log %s: %v\n", *cmdPtr, *auditLogPtr, err)
+			os.Exit(exitError)
+		}
+		problems, err := wikiImport(client, srcUsersSubs, srcUser.Email, *groupNamePtr, *outPtr, *dryRunPtr, audit)
+		audit.Close()
+		if err != nil {
+			fmt.Printf("main: %s: %v\n", *cmdPtr, err)
+			os.Exit(exitError)
+		}
+		if problems > 0 {
+			os.Exit(exitError)
 		}
 	default:
 		fmt.Printf("main.go: unknown sub command %s\n", *cmdPtr)
@@ -142,7 +671,12 @@ func userReport(u interface{}) {
 func fullSummaryReport(emailPtr *string, subscriptionCount int, loggedInUsersSubs []groupsclient.MemberInfo) {
 	fmt.Printf("%s is subscribed to %d groups, they are...\n", *emailPtr, subscriptionCount)
 	for _, subscription := range loggedInUsersSubs {
-		fmt.Printf("%s, ", subscription.GroupName)
+		switch {
+		case subscription.IsOwner(), subscription.IsModerator():
+			fmt.Printf("%s (%s), ", subscription.GroupName, subscription.ModStatus)
+		default:
+			fmt.Printf("%s, ", subscription.GroupName)
+		}
 	}
 }
 
@@ -159,6 +693,20 @@ func ContinuePrompt() {
 	}
 }
 
+// stdin is shared by every prompt so that input one prompt reads ahead is not lost to the next
+var stdin = bufio.NewReader(os.Stdin)
+
+// Prompt prints label and returns the trimmed line the user enters, io.EOF once there is no more input
+func Prompt(label string) (string, error) {
+	fmt.Print(label)
+	s, err := stdin.ReadString('\n')
+	if err == io.EOF && s != "" {
+		// a last line without a newline is still an answer
+		err = nil
+	}
+	return strings.TrimSpace(s), err
+}
+
 // YesNoPrompt asks yes/no questions using the label.
 func YesNoPrompt(label string, def bool) bool {
 	choices := "Y/n"
@@ -166,12 +714,11 @@ func YesNoPrompt(label string, def bool) bool {
 		choices = "y/N"
 	}
 
-	r := bufio.NewReader(os.Stdin)
 	var s string
 
 	for {
 		fmt.Printf("%s (%s) ", label, choices)
-		s, _ = r.ReadString('\n')
+		s, _ = stdin.ReadString('\n')
 		s = strings.TrimSpace(s)
 		if s == "" {
 			return def
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"main/groupsclient"
+	"os"
+	"strings"
+)
+
+// readEmailsFile reads one email address per line from path, blank lines and lines starting with # are skipped
+func readEmailsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	emails := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		emails = append(emails, line)
+	}
+	return emails, scanner.Err()
+}
+
+// targetEmails returns the single memberEmail if set, otherwise the addresses listed in emailsFile
+func targetEmails(memberEmail string, emailsFile string) ([]string, error) {
+	if memberEmail != "" {
+		return []string{memberEmail}, nil
+	}
+	if emailsFile != "" {
+		return readEmailsFile(emailsFile)
+	}
+	return nil, fmt.Errorf("one of --memberEmail or --emailsFile must be specified")
+}
+
+// bulkMemberAction applies action to every member in emails on each of the groups in adminSubs.
+// adminSubs are the authenticated user's own subscriptions, their Perms are checked before each action is taken and
+// every action attempted, successful or not, is recorded in audit.
+func bulkMemberAction(client *groupsclient.GroupsClient, action groupsclient.MemberAction, actor string, emails []string,
+	adminSubs []groupsclient.MemberInfo, reason string, audit *AuditLog) int {
+	actioned := 0
+	for _, email := range emails {
+		target, err := client.SearchMemberDetails(email)
+		if err != nil {
+			fmt.Printf("members %s: skipping %s: %v\n", action, email, err)
+			continue
+		}
+		for _, adminSub := range adminSubs {
+			entry := AuditEntry{
+				Actor:     actor,
+				Action:    string(action),
+				GroupID:   adminSub.GroupID,
+				GroupName: adminSub.GroupName,
+				Target:    email,
+				Reason:    reason,
+			}
+			if !action.Permitted(adminSub) {
+				fmt.Printf("members %s: %s does not have %s permission on %s, skipping\n", action, actor, action, adminSub.GroupName)
+				continue
+			}
+			memberId, err := client.GetMemberId(adminSub.GroupID, target.UserID)
+			if err != nil {
+				// not being a member of every group is expected, nothing to record
+				continue
+			}
+			if err := client.ApplyMemberAction(action, adminSub.GroupID, memberId); err != nil {
+				fmt.Printf("members %s: %s on %s failed: %v\n", action, email, adminSub.GroupName, err)
+				entry.Result = fmt.Sprintf("error: %v", err)
+			} else {
+				fmt.Printf("members %s: %s on %s done\n", action, email, adminSub.GroupName)
+				entry.Result = "ok"
+				actioned++
+			}
+			recordAudit(audit, entry)
+		}
+	}
+	return actioned
+}
+
+func recordAudit(audit *AuditLog, entry AuditEntry) {
+	if err := audit.Record(entry); err != nil {
+		fmt.Printf("audit: failed to record %+v: %v\n", entry, err)
+	}
+}
+
+// recordAuditResult records entry in audit with its Result set from err, the outcome of the action entry describes
+func recordAuditResult(audit *AuditLog, entry AuditEntry, err error) {
+	entry.Result = "ok"
+	if err != nil {
+		entry.Result = fmt.Sprintf("error: %v", err)
+	}
+	recordAudit(audit, entry)
+}
+
+// memberUpdateFlags holds the raw command line values used to build a groupsclient.MemberUpdate
+type memberUpdateFlags struct {
+	modStatus        string
+	postStatus       string
+	emailDelivery    string
+	messageSelection string
+	// notify is a comma separated list of field=value pairs, e.g. pending_msg_notify=email,sub_notify=none
+	notify          string
+	modPermissions  string
+	extraMemberData string
+}
+
+// memberUpdate parses f into a MemberUpdate, accepting the short names understood by the groupsclient Parse functions
+func (f memberUpdateFlags) memberUpdate() (groupsclient.MemberUpdate, error) {
+	var u groupsclient.MemberUpdate
+	var err error
+	if f.modStatus != "" {
+		if u.ModStatus, err = groupsclient.ParseModStatus(f.modStatus); err != nil {
+			return u, err
+		}
+	}
+	if f.postStatus != "" {
+		if u.PostStatus, err = groupsclient.ParsePostStatus(f.postStatus); err != nil {
+			return u, err
+		}
+	}
+	if f.emailDelivery != "" {
+		if u.EmailDelivery, err = groupsclient.ParseEmailDelivery(f.emailDelivery); err != nil {
+			return u, err
+		}
+	}
+	if f.messageSelection != "" {
+		if u.MessageSelection, err = groupsclient.ParseMessageSelection(f.messageSelection); err != nil {
+			return u, err
+		}
+	}
+	if f.notify != "" {
+		for _, pair := range strings.Split(f.notify, ",") {
+			field, value, found := strings.Cut(pair, "=")
+			if !found {
+				return u, fmt.Errorf("notify setting %q is not of the form field=value", pair)
+			}
+			setting, err := groupsclient.ParseNotifySetting(value)
+			if err != nil {
+				return u, err
+			}
+			if err := u.SetNotify(strings.TrimSpace(field), setting); err != nil {
+				return u, err
+			}
+		}
+	}
+	if f.modPermissions != "" {
+		for _, perm := range strings.Split(f.modPermissions, ",") {
+			u.ModPermissions = append(u.ModPermissions, strings.TrimSpace(perm))
+		}
+	}
+	if f.extraMemberData != "" {
+		if err := json.Unmarshal([]byte(f.extraMemberData), &u.ExtraMemberData); err != nil {
+			return u, fmt.Errorf("extraMemberData is not a JSON array of extra member data: %w", err)
+		}
+	}
+	return u, u.Validate()
+}
+
+// bulkMemberUpdate applies update to every member in emails on each of the groups in adminSubs where the
+// authenticated user's Perms allow it, recording each update in audit
+func bulkMemberUpdate(client *groupsclient.GroupsClient, update groupsclient.MemberUpdate, actor string, emails []string,
+	adminSubs []groupsclient.MemberInfo, reason string, audit *AuditLog) int {
+	updated := 0
+	for _, email := range emails {
+		target, err := client.SearchMemberDetails(email)
+		if err != nil {
+			fmt.Printf("members set: skipping %s: %v\n", email, err)
+			continue
+		}
+		for _, adminSub := range adminSubs {
+			if !update.Permitted(adminSub) {
+				fmt.Printf("members set: %s does not have permission to update members of %s, skipping\n", actor, adminSub.GroupName)
+				continue
+			}
+			memberId, err := client.GetMemberId(adminSub.GroupID, target.UserID)
+			if err != nil {
+				continue
+			}
+			entry := AuditEntry{
+				Actor:     actor,
+				Action:    fmt.Sprintf("set %+v", update),
+				GroupID:   adminSub.GroupID,
+				GroupName: adminSub.GroupName,
+				Target:    email,
+				Reason:    reason,
+			}
+			if _, err := client.UpdateGroupMember(adminSub.GroupID, memberId, update); err != nil {
+				fmt.Printf("members set: %s on %s failed: %v\n", email, adminSub.GroupName, err)
+				entry.Result = fmt.Sprintf("error: %v", err)
+			} else {
+				fmt.Printf("members set: %s on %s updated\n", email, adminSub.GroupName)
+				entry.Result = "ok"
+				updated++
+			}
+			recordAudit(audit, entry)
+		}
+	}
+	return updated
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"sort"
+)
+
+// printOrgSettings prints the settings of org in name order, with secrets redacted
+func printOrgSettings(org *groupsclient.Org) {
+	settings := org.Settings().Redacted()
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("org %s [OrgId %d, domain %s, ParentGroupId %d]\n", org.Title, org.ID, org.Domain, org.ParentGroupID)
+	for _, name := range names {
+		fmt.Printf("  %s = %q\n", name, settings[name])
+	}
+}
+
+// orgUpdate shows how each of settings would change the org, and once confirmed applies them, recording it in audit
+func orgUpdate(client *groupsclient.GroupsClient, actor string, settings groupsclient.OrgSettings, audit *AuditLog) error {
+	if len(settings) == 0 {
+		return fmt.Errorf("no settings given, use -set name=value")
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	org, err := client.GetOrg()
+	if err != nil {
+		return err
+	}
+	current := org.Settings().Redacted()
+	wanted := settings.Redacted()
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("orgUpdate: changing %s\n", org.Title)
+	changes := 0
+	for _, name := range names {
+		if org.Settings()[name] == settings[name] {
+			fmt.Printf("  %s: %q unchanged\n", name, current[name])
+			continue
+		}
+		changes++
+		fmt.Printf("  %s: %q -> %q\n", name, current[name], wanted[name])
+	}
+	if changes == 0 {
+		fmt.Println("orgUpdate: nothing to change")
+		return nil
+	}
+	ContinuePrompt()
+	_, err = client.UpdateOrg(settings)
+	recordAuditResult(audit, AuditEntry{Actor: actor, Action: "update org", GroupID: org.ParentGroupID,
+		GroupName: org.Title, Target: settingsFlag(wanted).String()}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("orgUpdate: updated %d setting(s)\n", changes)
+	return nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// pendingMsgPreviewLines is the number of lines of a pending message's body shown during review
+const pendingMsgPreviewLines = 60
+
+// printPendingMsg shows a pending message in enough detail for a moderator to decide what to do with it
+func printPendingMsg(i int, total int, msg groupsclient.PendingMsg) {
+	fmt.Printf("\n---- pending message %d of %d [id %d, group %s] ----\n", i+1, total, msg.ID, msg.GroupName)
+	fmt.Printf("Sender:  %s <%s>\n", msg.SenderName, msg.SenderEmail)
+	fmt.Printf("Held:    %s (%s)\n", msg.Created, msg.Type)
+	if msg.ClaimingUser.Name != "" {
+		fmt.Printf("Claimed: by %s on %s\n", msg.ClaimingUser.Name, msg.ClaimedDate)
+	}
+	if msg.VirusName != "" {
+		fmt.Printf("VIRUS:   %s\n", msg.VirusName)
+	}
+	parsed, err := msg.Parse()
+	if err != nil {
+		// fall back to the body groups.io extracted
+		fmt.Printf("Subject: %s\n\n", groupsclient.Printable(msg.Subject))
+		fmt.Println(groupsclient.Printable(msg.MessageBody))
+		return
+	}
+	fmt.Println()
+	fmt.Print(parsed.Preview(pendingMsgPreviewLines))
+}
+
+// reviewPendingMsgs walks a moderator through pendingMessages one at a time, taking the action they choose on each
+// and recording it in audit. subs are actor's subscriptions, senders who are approved and trusted are unmoderated in
+// the group their message was held in and, when trustAll is set, in each of subs.
+func reviewPendingMsgs(client *groupsclient.GroupsClient, actor string, pendingMessages []groupsclient.PendingMsg,
+	subs []groupsclient.MemberInfo, trustAll bool, audit *AuditLog) {
+	var trustIn []groupsclient.MemberInfo
+	if trustAll {
+		trustIn = subs
+	}
+	for i, msg := range pendingMessages {
+		printPendingMsg(i, len(pendingMessages), msg)
+		var action groupsclient.PendingMsgAction
+		var reason string
+		var err error
+	choice:
+		for {
+			answer, promptErr := Prompt("[a]pprove, approve and [t]rust, [r]eject, [d]elete, [c]laim, [s]kip or [q]uit? ")
+			if promptErr != nil {
+				// no more input, treat it as quit
+				fmt.Println()
+				return
+			}
+			switch strings.ToLower(answer) {
+			case "a":
+				action, err = groupsclient.ApprovePendingMsgAction, client.ApprovePendingMsg(msg)
+			case "t":
+				var trusted int
+				heldIn, _ := subFor(subs, msg.GroupID)
+				trusted, err = client.ApproveAndTrustPendingMsg(msg, heldIn, trustIn)
+				action = groupsclient.TrustPendingMsgAction
+				reason = fmt.Sprintf("sender unmoderated in %d group(s)", trusted)
+			case "r":
+				if reason, err = Prompt("Reason sent to the sender (leave empty to reject silently): "); err != nil {
+					fmt.Println()
+					return
+				}
+				action, err = groupsclient.RejectPendingMsgAction, client.RejectPendingMsg(msg, reason)
+			case "d":
+				action, err = groupsclient.DeletePendingMsgAction, client.DeletePendingMsg(msg)
+			case "c":
+				action, err = groupsclient.ClaimPendingMsgAction, client.ClaimPendingMsg(msg)
+			case "s":
+			case "q":
+				return
+			default:
+				continue
+			}
+			break choice
+		}
+		if action == "" {
+			continue
+		}
+		entry := AuditEntry{
+			Actor:     actor,
+			Action:    string(action) + " pending message",
+			GroupID:   msg.GroupID,
+			GroupName: msg.GroupName,
+			Target:    fmt.Sprintf("%d %s: %s", msg.ID, msg.SenderEmail, msg.Subject),
+			Reason:    reason,
+			Result:    "ok",
+		}
+		if err != nil {
+			fmt.Printf("pendReview: %s failed: %v\n", action, err)
+			entry.Result = fmt.Sprintf("error: %v", err)
+		} else {
+			fmt.Printf("pendReview: %s done\n", action)
+		}
+		recordAudit(audit, entry)
+	}
+}
+
+// pendingMsgSummary returns a one line summary of msg
+func pendingMsgSummary(msg groupsclient.PendingMsg) string {
+	return fmt.Sprintf("group: %s, from: %s <%s>, subject: %s", msg.GroupName, msg.SenderName, msg.SenderEmail, strings.TrimSpace(msg.Subject))
+}
+
+// filteredPendingMsgs gets the pending messages of every group the authenticated user moderates and returns those
+// held in a group matching groupRe, for between minAge and maxAge, from a sender matching sender
+func filteredPendingMsgs(client *groupsclient.GroupsClient, groupRe string, minAge time.Duration, maxAge time.Duration,
+	sender string) ([]groupsclient.PendingMsg, error) {
+	filter := groupsclient.PendingMsgFilter{MinAge: minAge, MaxAge: maxAge, Sender: sender}
+	if groupRe != "" {
+		re, err := regexp.Compile(groupRe)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --filter %q: %w", groupRe, err)
+		}
+		filter.Group = re
+	}
+	pendingMessages, _, err := client.GetPendingMsgList(filter.Group)
+	if err != nil {
+		return nil, err
+	}
+	return groupsclient.FilterPendingMsgs(pendingMessages, filter), nil
+}
+
+// autoModerate evaluates rules against every pending message in scope and, unless dryRun is set, approves or
+// rejects them as decided. Each decision is printed with the rule that matched and recorded in audit when applied.
+func autoModerate(client *groupsclient.GroupsClient, actor string, rules *groupsclient.ModerationRules, groupRe string,
+	minAge time.Duration, maxAge time.Duration, sender string, dryRun bool, audit *AuditLog) error {
+	pendingMessages, err := filteredPendingMsgs(client, groupRe, minAge, maxAge, sender)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("pendAutoMod: evaluating %d pending message(s)\n", len(pendingMessages))
+	for _, msg := range pendingMessages {
+		result := rules.Evaluate(msg)
+		fmt.Printf("pendAutoMod: %-7s [%s] %s\n", result.Decision, result.Rule, pendingMsgSummary(msg))
+		if dryRun || result.Decision == groupsclient.DecisionHold {
+			continue
+		}
+		entry := AuditEntry{
+			Actor:     actor,
+			Action:    fmt.Sprintf("auto %s pending message", result.Decision),
+			GroupID:   msg.GroupID,
+			GroupName: msg.GroupName,
+			Target:    fmt.Sprintf("%d %s: %s", msg.ID, msg.SenderEmail, msg.Subject),
+			Reason:    fmt.Sprintf("rule %q %s", result.Rule, result.RejectReason),
+			Result:    "ok",
+		}
+		if err := client.ApplyModerationResult(result); err != nil {
+			fmt.Printf("pendAutoMod: %s failed: %v\n", result.Decision, err)
+			entry.Result = fmt.Sprintf("error: %v", err)
+		}
+		recordAudit(audit, entry)
+	}
+	return nil
+}
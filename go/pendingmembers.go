@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"regexp"
+	"strings"
+)
+
+// filteredPendingMembers gets the pending members of every group the authenticated user manages whose name matches
+// groupRe
+func filteredPendingMembers(client *groupsclient.GroupsClient, groupRe string) ([]groupsclient.MemberInfo, error) {
+	var re *regexp.Regexp
+	if groupRe != "" {
+		var err error
+		if re, err = regexp.Compile(groupRe); err != nil {
+			return nil, fmt.Errorf("invalid --filter %q: %w", groupRe, err)
+		}
+	}
+	return client.GetPendingMembers(re)
+}
+
+// printPendingMember shows an applicant's profile and their answers to the group's member data questions
+func printPendingMember(i int, total int, member groupsclient.MemberInfo) {
+	fmt.Printf("\n---- pending member %d of %d [group %s] ----\n", i+1, total, member.GroupName)
+	fmt.Printf("Name:     %s (%s)\n", member.FullName, member.UserName)
+	fmt.Printf("Email:    %s\n", member.Email)
+	fmt.Printf("Applied:  %s\n", member.Created)
+	if member.Location != "" {
+		fmt.Printf("Location: %s\n", member.Location)
+	}
+	if member.Website != "" {
+		fmt.Printf("Website:  %s\n", member.Website)
+	}
+	if member.AboutMe != "" {
+		fmt.Printf("About:    %s\n", member.AboutMe)
+	}
+	for _, answer := range member.ExtraMemberData {
+		fmt.Printf("Answer %d: %s\n", answer.ColID, answer.Value())
+	}
+}
+
+// reviewPendingMembers walks a moderator through pending one applicant at a time, approving or rejecting each as
+// they choose and recording it in audit
+func reviewPendingMembers(client *groupsclient.GroupsClient, actor string, pending []groupsclient.MemberInfo, audit *AuditLog) {
+	for i, member := range pending {
+		printPendingMember(i, len(pending), member)
+		var action string
+		var reason string
+		var err error
+	choice:
+		for {
+			answer, promptErr := Prompt("[a]pprove, [r]eject, [s]kip or [q]uit? ")
+			if promptErr != nil {
+				// no more input, treat it as quit
+				fmt.Println()
+				return
+			}
+			switch strings.ToLower(answer) {
+			case "a":
+				action, err = "approve member", client.ApprovePendingMember(member)
+			case "r":
+				if reason, err = Prompt("Reason sent to the applicant (leave empty to reject silently): "); err != nil {
+					fmt.Println()
+					return
+				}
+				action, err = "reject member", client.RejectPendingMember(member, reason)
+			case "s":
+			case "q":
+				return
+			default:
+				continue
+			}
+			break choice
+		}
+		if action == "" {
+			continue
+		}
+		recordPendingMemberAction(audit, actor, action, member, reason, err)
+	}
+}
+
+// autoApprovePendingMembers approves every applicant in pending whose email address is in one of trustedDomains,
+// unless dryRun is set, and returns the applicants left for a moderator to review
+func autoApprovePendingMembers(client *groupsclient.GroupsClient, actor string, pending []groupsclient.MemberInfo,
+	trustedDomains []string, dryRun bool, audit *AuditLog) []groupsclient.MemberInfo {
+	remaining := make([]groupsclient.MemberInfo, 0, len(pending))
+	for _, member := range pending {
+		if !member.EmailDomainIn(trustedDomains) {
+			remaining = append(remaining, member)
+			continue
+		}
+		fmt.Printf("pendMembers: approve %s <%s> on %s, trusted domain\n", member.FullName, member.Email, member.GroupName)
+		if dryRun {
+			continue
+		}
+		err := client.ApprovePendingMember(member)
+		recordPendingMemberAction(audit, actor, "auto approve member", member, "trusted domain", err)
+	}
+	return remaining
+}
+
+func recordPendingMemberAction(audit *AuditLog, actor string, action string, member groupsclient.MemberInfo, reason string, err error) {
+	entry := AuditEntry{
+		Actor:     actor,
+		Action:    action,
+		GroupID:   member.GroupID,
+		GroupName: member.GroupName,
+		Target:    member.Email,
+		Reason:    reason,
+		Result:    "ok",
+	}
+	if err != nil {
+		fmt.Printf("pendMembers: %s %s failed: %v\n", action, member.Email, err)
+		entry.Result = fmt.Sprintf("error: %v", err)
+	}
+	recordAudit(audit, entry)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"main/groupsclient"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// listFlag collects the values of a repeated flag
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// readsStdin reports whether readPostBody reads the body from stdin given path
+func readsStdin(path string) bool {
+	return path == "" || path == "-"
+}
+
+// readPostBody reads the body of a post from path, or from stdin when path is empty or -. The body is taken to be
+// Markdown when markdown is set or path has a .md or .markdown extension.
+func readPostBody(path string, markdown bool) (string, bool, error) {
+	var body []byte
+	var err error
+	if readsStdin(path) {
+		body, err = io.ReadAll(os.Stdin)
+	} else {
+		body, err = os.ReadFile(path)
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".md", ".markdown":
+			markdown = true
+		}
+	}
+	return string(body), markdown, err
+}
+
+// postTargets returns the subscriptions in subs to the group called name or, when name is empty, to the groups
+// matching re
+func postTargets(subs []groupsclient.MemberInfo, name string, re string) ([]groupsclient.MemberInfo, error) {
+	if name != "" {
+		for _, sub := range subs {
+			if strings.EqualFold(sub.GroupName, name) {
+				return []groupsclient.MemberInfo{sub}, nil
+			}
+		}
+		return nil, fmt.Errorf("you are not subscribed to %s", name)
+	}
+	if re == "" {
+		return nil, fmt.Errorf("one of --groupName or --filter must be specified")
+	}
+	targets, err := filterSrcUserSubs(re, subs)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("you are not subscribed to any group matching %q", re)
+	}
+	return targets, nil
+}
+
+// printPost shows what sub's group will receive when post is sent to it
+func printPost(sub groupsclient.MemberInfo, post groupsclient.Post) {
+	body, plain := post.RenderedBody()
+	format := "html"
+	if plain {
+		format = "plain text"
+	}
+	fmt.Printf("==== %s ====\n", sub.GroupName)
+	fmt.Printf("Subject: %s\n", post.RenderedSubject())
+	for _, path := range post.Attachments {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Printf("Attachment: %s (%d bytes)\n", filepath.Base(path), info.Size())
+		}
+	}
+	fmt.Printf("Body (%s):\n%s\n", format, body)
+}
+
+// postMessage sends post to each of targets in which actor has permission to post, recording each post in audit.
+// With dryRun set it only shows what each group would receive, with confirm set it sends without asking first.
+// Returns the number of groups posted to.
+func postMessage(client *groupsclient.GroupsClient, actor string, targets []groupsclient.MemberInfo, post groupsclient.Post,
+	dryRun bool, confirm bool, audit *AuditLog) (int, error) {
+	if err := post.Validate(); err != nil {
+		return 0, err
+	}
+	permitted := make([]groupsclient.MemberInfo, 0, len(targets))
+	for _, sub := range targets {
+		if !sub.Perms.CanPost {
+			fmt.Printf("post: %s cannot post to %s, skipping\n", actor, sub.GroupName)
+			continue
+		}
+		permitted = append(permitted, sub)
+	}
+	if dryRun {
+		for _, sub := range permitted {
+			printPost(sub, post)
+		}
+		fmt.Printf("post: dry run, would post %q to %d group(s)\n", post.RenderedSubject(), len(permitted))
+		return 0, nil
+	}
+	if len(permitted) == 0 {
+		return 0, nil
+	}
+	names := make([]string, 0, len(permitted))
+	for _, sub := range permitted {
+		names = append(names, sub.GroupName)
+	}
+	fmt.Printf("post: sending %q to %s\n", post.RenderedSubject(), strings.Join(names, ", "))
+	if !confirm {
+		ContinuePrompt()
+	}
+
+	posted := 0
+	for _, sub := range permitted {
+		entry := AuditEntry{
+			Actor:     actor,
+			Action:    "post",
+			GroupID:   sub.GroupID,
+			GroupName: sub.GroupName,
+			Target:    post.RenderedSubject(),
+			Result:    "ok",
+		}
+		if msg, err := client.PostMessage(sub.GroupID, post); err != nil {
+			fmt.Printf("post: %s: FAILED %v\n", sub.GroupName, err)
+			entry.Result = fmt.Sprintf("error: %v", err)
+		} else {
+			fmt.Printf("post: %s: posted message %d\n", sub.GroupName, msg.ID)
+			posted++
+		}
+		recordAudit(audit, entry)
+	}
+	return posted, nil
+}
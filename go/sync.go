@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+)
+
+// Exit codes of the sync and drift commands, suitable for CI drift checks
+const (
+	exitInSync = 0
+	exitError  = 1
+	exitDrift  = 2
+)
+
+// syncSpec plans the changes needed to bring the Org in line with the spec at specPath, prints the plan and, when
+// confirm is set, applies it, recording each change in audit. Returns the exit code of the command.
+func syncSpec(client *groupsclient.GroupsClient, actor string, specPath string, confirm bool, audit *AuditLog) int {
+	spec, err := groupsclient.LoadSpec(specPath)
+	if err != nil {
+		fmt.Printf("sync: %v\n", err)
+		return exitError
+	}
+	plan, err := client.PlanSync(spec, actor)
+	if err != nil {
+		fmt.Printf("sync: Error planning changes: %v\n", err)
+		return exitError
+	}
+	if !plan.HasChanges() {
+		fmt.Printf("sync: %d group(s) in %s match groups.io, nothing to do\n", len(spec.Groups), specPath)
+		return exitInSync
+	}
+	fmt.Printf("sync: %d change(s) needed\n", len(plan.Changes))
+	for _, change := range plan.Changes {
+		fmt.Println(change)
+	}
+	if !confirm {
+		fmt.Println("sync: run again with --confirm to apply")
+		return exitDrift
+	}
+
+	failed := 0
+	for _, result := range client.ApplyPlan(plan) {
+		entry := AuditEntry{
+			Actor:     actor,
+			Action:    "sync " + string(result.Change.Kind),
+			GroupID:   result.Change.GroupID,
+			GroupName: result.Change.Group,
+			Target:    result.Change.String(),
+			Reason:    specPath,
+			Result:    "ok",
+		}
+		if result.Err != nil {
+			failed++
+			fmt.Printf("sync: FAILED %s: %v\n", result.Change, result.Err)
+			entry.Result = fmt.Sprintf("error: %v", result.Err)
+		} else {
+			fmt.Printf("sync: applied %s\n", result.Change)
+		}
+		recordAudit(audit, entry)
+	}
+	if failed > 0 {
+		fmt.Printf("sync: %d of %d change(s) failed\n", failed, len(plan.Changes))
+		return exitError
+	}
+	return exitInSync
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"strings"
+)
+
+// topicActions maps the topic* commands to the TopicAction each takes
+var topicActions = map[string]groupsclient.TopicAction{
+	"topicLock":     groupsclient.LockTopicAction,
+	"topicUnlock":   groupsclient.UnlockTopicAction,
+	"topicSticky":   groupsclient.StickyTopicAction,
+	"topicUnsticky": groupsclient.UnstickyTopicAction,
+	"topicHashtags": groupsclient.HashtagsTopicAction,
+	"topicMove":     groupsclient.MoveTopicAction,
+	"topicDelete":   groupsclient.DeleteTopicAction,
+}
+
+// subFor returns the subscription in subs to groupId
+func subFor(subs []groupsclient.MemberInfo, groupId int) (groupsclient.MemberInfo, bool) {
+	for _, sub := range subs {
+		if sub.GroupID == groupId {
+			return sub, true
+		}
+	}
+	return groupsclient.MemberInfo{}, false
+}
+
+// adminSubFor returns the subscription in subs to the subgroup name, provided permitted allows what is to be done
+// with it, such as a HashtagAction's Permitted
+func adminSubFor(subs []groupsclient.MemberInfo, name string, permitted func(groupsclient.MemberInfo) bool) (groupsclient.MemberInfo, error) {
+	for _, sub := range subs {
+		if strings.EqualFold(sub.GroupName, name) {
+			if !permitted(sub) {
+				return groupsclient.MemberInfo{}, fmt.Errorf("your subscription to %s does not have the permission needed", sub.GroupName)
+			}
+			return sub, nil
+		}
+	}
+	return groupsclient.MemberInfo{}, fmt.Errorf("%s is not one of your groups", name)
+}
+
+// moderateTopic takes action on the topic identified by ref, an id or URL, after checking the Perms of actor's
+// subscription in subs to the topic's group, and records it in audit. hashtags are the new hashtags of
+// HashtagsTopicAction and destGroup the subgroup MoveTopicAction moves the topic to.
+func moderateTopic(client *groupsclient.GroupsClient, actor string, action groupsclient.TopicAction, ref string,
+	hashtags []string, destGroup string, reason string, subs []groupsclient.MemberInfo, audit *AuditLog) error {
+	topicId, err := groupsclient.ParseTopicID(ref)
+	if err != nil {
+		return err
+	}
+	topic, err := client.GetTopic(topicId)
+	if err != nil {
+		return err
+	}
+	adminSub, ok := subFor(subs, topic.GroupID)
+	if !ok || !action.Permitted(adminSub) {
+		return fmt.Errorf("%s does not have edit archives permission on the group of topic %d", actor, topic.ID)
+	}
+	fmt.Printf("topic %s: %q in %s, %d message(s)\n", action, topic.Subject, adminSub.GroupName, topic.NumMessages)
+
+	entry := AuditEntry{
+		Actor:     actor,
+		Action:    "topic " + string(action),
+		GroupID:   topic.GroupID,
+		GroupName: adminSub.GroupName,
+		Target:    fmt.Sprintf("topic %d %q", topic.ID, topic.Subject),
+		Reason:    reason,
+	}
+	switch action {
+	case groupsclient.LockTopicAction, groupsclient.UnlockTopicAction:
+		_, err = client.LockTopic(*topic, action == groupsclient.LockTopicAction)
+	case groupsclient.StickyTopicAction, groupsclient.UnstickyTopicAction:
+		_, err = client.SetTopicSticky(*topic, action == groupsclient.StickyTopicAction)
+	case groupsclient.HashtagsTopicAction:
+		current := make([]string, 0, len(topic.Hashtags))
+		for _, hashtag := range topic.Hashtags {
+			current = append(current, hashtag.Name)
+		}
+		fmt.Printf("  hashtags: %q -> %q\n", strings.Join(current, ","), strings.Join(hashtags, ","))
+		entry.Target += " hashtags " + strings.Join(hashtags, ",")
+		_, err = client.SetTopicHashtags(*topic, hashtags)
+	case groupsclient.MoveTopicAction:
+		if destGroup == "" {
+			return fmt.Errorf("--groupName not specified, the group to move the topic to")
+		}
+		var dest *groupsclient.Group
+		if dest, err = client.FindGroup(destGroup); err != nil {
+			return err
+		}
+		if destSub, ok := subFor(subs, dest.ID); !ok || !action.Permitted(destSub) {
+			return fmt.Errorf("%s does not have edit archives permission on %s", actor, dest.Name)
+		}
+		fmt.Printf("  moving to %s\n", dest.Name)
+		ContinuePrompt()
+		entry.Target += " to " + dest.Name
+		err = client.MoveTopic(*topic, dest.ID)
+	case groupsclient.DeleteTopicAction:
+		fmt.Println("  deleting the topic removes all of its messages for good")
+		ContinuePrompt()
+		err = client.DeleteTopic(*topic)
+	default:
+		return fmt.Errorf("unknown topic action %q", action)
+	}
+	recordAuditResult(audit, entry, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: done\n", entry.Action)
+	return nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"main/groupsclient"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// webhookExecTimeout bounds how long a -onEvent command may run for a single event
+const webhookExecTimeout = 30 * time.Second
+
+// serveWebhooks listens on addr for groups.io webhook deliveries signed with secret, logging each event and, when set,
+// running onEvent and forwarding the event to forwardTo signed with forwardSecret. events limits the actions passed to
+// onEvent and forwardTo.
+func serveWebhooks(addr string, secret string, events string, onEvent string, forwardTo string, forwardSecret string) error {
+	if secret == "" {
+		return fmt.Errorf("no webhook secret, use -webhookSecret or set GROUPSIO_WEBHOOK_SECRET")
+	}
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	var actions []string
+	if events != "" {
+		actions = strings.Split(events, ",")
+	}
+	handlers := []groupsclient.WebhookHandler{groupsclient.LogWebhookHandler(logger)}
+	if onEvent != "" {
+		args := strings.Fields(onEvent)
+		handlers = append(handlers, groupsclient.OnlyEvents(actions,
+			groupsclient.ExecWebhookHandler(webhookExecTimeout, args[0], args[1:]...)))
+	}
+	if forwardTo != "" {
+		handlers = append(handlers, groupsclient.OnlyEvents(actions,
+			groupsclient.ForwardWebhookHandler(&http.Client{Timeout: 30 * time.Second}, forwardTo, forwardSecret)))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", &groupsclient.WebhookServer{Secret: secret, Handlers: handlers, Logger: logger})
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	logger.Printf("serveWebhooks: listening on %s/webhook with %d handler(s)", addr, len(handlers))
+	return server.ListenAndServe()
+}
+
+// splitList splits a comma separated list, such as of webhook event types or hashtags, dropping empty items
+func splitList(s string) []string {
+	list := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// webhooksList prints the webhooks registered on the subgroup name
+func webhooksList(client *groupsclient.GroupsClient, name string) error {
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	hooks, err := client.GetWebhooks(group.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("webhooksList: %s has %d webhook(s)\n", group.Name, len(hooks))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tURL\tEVENTS\tSECRET\tUPDATED")
+	for _, hook := range hooks {
+		hook = groupsclient.Redact(hook)
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", hook.ID, hook.URL, strings.Join(hook.Events, ","), hook.Secret, hook.Updated)
+	}
+	return w.Flush()
+}
+
+// webhookTarget describes hook in audit entries, leaving out its secret
+func webhookTarget(hook groupsclient.Webhook) string {
+	return fmt.Sprintf("webhook %d %s for %s", hook.ID, hook.URL, strings.Join(hook.Events, ","))
+}
+
+// webhookCreate registers hook on the subgroup name, recording it in audit
+func webhookCreate(client *groupsclient.GroupsClient, actor string, name string, hook groupsclient.Webhook, audit *AuditLog) error {
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	if err := hook.Validate(); err != nil {
+		return err
+	}
+	fmt.Printf("webhookCreate: registering %s for %s on %s\n", hook.URL, strings.Join(hook.Events, ","), group.Name)
+	ContinuePrompt()
+	created, err := client.CreateWebhook(group.ID, hook)
+	if err == nil {
+		hook.ID = created.ID
+	}
+	recordAuditResult(audit, AuditEntry{Actor: actor, Action: "create webhook", GroupID: group.ID, GroupName: group.Name,
+		Target: webhookTarget(hook)}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("webhookCreate: created webhook %d on %s\n", created.ID, group.Name)
+	return nil
+}
+
+// webhookUpdate replaces the webhook webhookId on the subgroup name with hook, a URL, events or secret not given are
+// kept from the existing webhook, and records it in audit
+func webhookUpdate(client *groupsclient.GroupsClient, actor string, name string, webhookId int, hook groupsclient.Webhook,
+	audit *AuditLog) error {
+	group, existing, err := findWebhook(client, name, webhookId)
+	if err != nil {
+		return err
+	}
+	want := *existing
+	if hook.URL != "" {
+		want.URL = hook.URL
+	}
+	if len(hook.Events) > 0 {
+		want.Events = hook.Events
+	}
+	if hook.Secret != "" {
+		want.Secret = hook.Secret
+	}
+	if err := want.Validate(); err != nil {
+		return err
+	}
+	fmt.Printf("webhookUpdate: changing webhook %d on %s\n", existing.ID, group.Name)
+	fmt.Printf("  url: %q -> %q\n", existing.URL, want.URL)
+	fmt.Printf("  events: %q -> %q\n", strings.Join(existing.Events, ","), strings.Join(want.Events, ","))
+	if want.Secret != existing.Secret {
+		fmt.Println("  secret: changed")
+	}
+	ContinuePrompt()
+	_, err = client.UpdateWebhook(want)
+	recordAuditResult(audit, AuditEntry{Actor: actor, Action: "update webhook", GroupID: group.ID, GroupName: group.Name,
+		Target: webhookTarget(want)}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("webhookUpdate: updated webhook %d on %s\n", existing.ID, group.Name)
+	return nil
+}
+
+// webhookDelete removes the webhook webhookId from the subgroup name, recording it in audit
+func webhookDelete(client *groupsclient.GroupsClient, actor string, name string, webhookId int, audit *AuditLog) error {
+	group, existing, err := findWebhook(client, name, webhookId)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("webhookDelete: deleting webhook %d, %s, from %s\n", existing.ID, existing.URL, group.Name)
+	ContinuePrompt()
+	err = client.DeleteWebhook(group.ID, existing.ID)
+	recordAuditResult(audit, AuditEntry{Actor: actor, Action: "delete webhook", GroupID: group.ID, GroupName: group.Name,
+		Target: webhookTarget(*existing)}, err)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("webhookDelete: deleted webhook %d from %s\n", existing.ID, group.Name)
+	return nil
+}
+
+// findWebhook returns the subgroup name and its webhook webhookId
+func findWebhook(client *groupsclient.GroupsClient, name string, webhookId int) (*groupsclient.Group, *groupsclient.Webhook, error) {
+	if webhookId == 0 {
+		return nil, nil, fmt.Errorf("--webhookId not specified, use -cmd webhooksList to find it")
+	}
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	hooks, err := client.GetWebhooks(group.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, hook := range hooks {
+		if hook.ID == webhookId {
+			hook.GroupID = group.ID
+			return group, &hook, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%s has no webhook %d", group.Name, webhookId)
+}
+
+// webhooksRegister makes sure hook is registered on every subgroup whose name matches re, printing what it does, or
+// with dryRun would do, to each, recording each change in audit. Returns the number of groups that failed.
+func webhooksRegister(client *groupsclient.GroupsClient, actor string, re string, hook groupsclient.Webhook, dryRun bool,
+	audit *AuditLog) (int, error) {
+	if re == "" {
+		return 0, fmt.Errorf("--filter not specified, give a regex matching the subgroups to register on")
+	}
+	groups, _, err := client.ListGroups()
+	if err != nil {
+		return 0, err
+	}
+	groups, err = filterGroups(re, groups)
+	if err != nil {
+		return 0, err
+	}
+	if len(groups) == 0 {
+		return 0, fmt.Errorf("no subgroups match %q", re)
+	}
+	if !dryRun {
+		fmt.Printf("webhooksRegister: registering %s for %s on %d group(s)\n", hook.URL, strings.Join(hook.Events, ","), len(groups))
+		ContinuePrompt()
+	}
+	failed := 0
+	for _, result := range client.RegisterWebhook(groups, hook, dryRun) {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Printf("webhooksRegister: %s: FAILED %v\n", result.Group.Name, result.Err)
+		case dryRun && result.Action != groupsclient.WebhookUnchanged:
+			fmt.Printf("webhooksRegister: %s: would %s\n", result.Group.Name, result.Action)
+		default:
+			fmt.Printf("webhooksRegister: %s: %s\n", result.Group.Name, result.Action)
+		}
+		if !dryRun && result.Action != groupsclient.WebhookUnchanged {
+			recordAuditResult(audit, AuditEntry{Actor: actor, Action: "register webhook " + string(result.Action),
+				GroupID: result.Group.ID, GroupName: result.Group.Name, Target: webhookTarget(hook)}, result.Err)
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"main/groupsclient"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// wikiExport writes every page of the wiki of the subgroup name to a Markdown file in dir
+func wikiExport(client *groupsclient.GroupsClient, name string, dir string) error {
+	if dir == "" {
+		return fmt.Errorf("--out not specified, the directory to export into")
+	}
+	group, err := client.FindGroup(name)
+	if err != nil {
+		return err
+	}
+	pages, err := client.GetWiki(group.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	used := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		fileName := page.WikiFileName()
+		// a renamed file can itself collide with the file of another page, keep going until the name is free
+		base := strings.TrimSuffix(fileName, ".md")
+		for i := 1; used[fileName]; i++ {
+			fileName = fmt.Sprintf("%s-%d.md", base, page.ID)
+			if i > 1 {
+				fileName = fmt.Sprintf("%s-%d-%d.md", base, page.ID, i)
+			}
+		}
+		used[fileName] = true
+		data, err := page.Markdown()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, fileName), data, 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("wikiExport: wrote %d page(s) from %s to %s\n", len(pages), group.Name, dir)
+	return nil
+}
+
+// wikiUpdate is an edited page wikiImport will write back to groups.io from file
+type wikiUpdate struct {
+	file string
+	page groupsclient.WikiPage
+}
+
+// wikiImport updates the pages of the wiki of the subgroup name from the Markdown files in dir that have been edited
+// since they were exported, once the user has confirmed the list of updates. Pages edited on groups.io since the
+// export are conflicts and are left alone. With dryRun set it only reports what it would do. Returns the number of
+// conflicts and failures.
+func wikiImport(client *groupsclient.GroupsClient, subs []groupsclient.MemberInfo, actor string, name string, dir string,
+	dryRun bool, audit *AuditLog) (int, error) {
+	if dir == "" {
+		return 0, fmt.Errorf("--out not specified, the directory wikiExport wrote to")
+	}
+	sub, err := adminSubFor(subs, name, func(sub groupsclient.MemberInfo) bool { return sub.Perms.ManageWiki })
+	if err != nil {
+		return 0, err
+	}
+	pages, err := client.GetWiki(sub.GroupID)
+	if err != nil {
+		return 0, err
+	}
+	remote := make(map[int]groupsclient.WikiPage, len(pages))
+	for _, page := range pages {
+		remote[page.ID] = page
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(files)
+
+	problems := 0
+	updates := make([]wikiUpdate, 0)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return problems, err
+		}
+		fm, body, err := groupsclient.ParseWikiMarkdown(data)
+		if err != nil {
+			problems++
+			fmt.Printf("wikiImport: %s: %v\n", file, err)
+			continue
+		}
+		page, ok := remote[fm.ID]
+		if !ok {
+			problems++
+			fmt.Printf("wikiImport: %s: CONFLICT page %d no longer exists in %s\n", file, fm.ID, sub.GroupName)
+			continue
+		}
+		switch groupsclient.PlanWikiImport(fm, body, page) {
+		case groupsclient.WikiUnchanged:
+			continue
+		case groupsclient.WikiConflict:
+			problems++
+			fmt.Printf("wikiImport: %s: CONFLICT %q was changed on groups.io by %s at %s, after the export at %s\n",
+				file, page.Title, page.LastEditorName, page.Updated, fm.Updated)
+			continue
+		}
+		page.Title = fm.Title
+		page.Body = body
+		if page.GroupID == 0 {
+			page.GroupID = sub.GroupID
+		}
+		updates = append(updates, wikiUpdate{file: file, page: page})
+	}
+	if len(updates) == 0 {
+		fmt.Printf("wikiImport: no edited pages to update, %d conflict(s) or failure(s)\n", problems)
+		return problems, nil
+	}
+	for _, u := range updates {
+		fmt.Printf("wikiImport: %s: update %q in %s\n", u.file, u.page.Title, sub.GroupName)
+	}
+	if dryRun {
+		return problems, nil
+	}
+	ContinuePrompt()
+
+	updated := 0
+	for _, u := range updates {
+		result, err := client.UpdateWikiPage(u.page)
+		recordAuditResult(audit, AuditEntry{Actor: actor, Action: "wiki update", GroupID: sub.GroupID,
+			GroupName: sub.GroupName, Target: fmt.Sprintf("page %d %q", u.page.ID, u.page.Title), Reason: u.file}, err)
+		if err != nil {
+			problems++
+			fmt.Printf("wikiImport: %s: FAILED %v\n", u.file, err)
+			continue
+		}
+		updated++
+		fmt.Printf("wikiImport: %s: updated %q\n", u.file, u.page.Title)
+		// refresh the front matter so that the next import compares against this version of the page
+		if data, err := result.Markdown(); err == nil {
+			if err := os.WriteFile(u.file, data, 0o644); err != nil {
+				fmt.Printf("wikiImport: %s: Error refreshing front matter: %v\n", u.file, err)
+			}
+		}
+	}
+	fmt.Printf("wikiImport: %d page(s) updated, %d conflict(s) or failure(s)\n", updated, problems)
+	return problems, nil
+}